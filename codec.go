@@ -22,9 +22,17 @@
 package uuid
 
 import (
+	"bufio"
 	"bytes"
+	"encoding/binary"
 	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+	"unicode"
+	"unicode/utf8"
 )
 
 // FromBytes returns a UUID generated from the raw byte slice input.
@@ -45,6 +53,95 @@ func FromBytesOrNil(input []byte) UUID {
 	return uuid
 }
 
+// FromFields returns a UUID assembled from the fields of the RFC-4122 layout,
+// each written big-endian in order. The version and variant are taken as-is
+// from timeHiAndVersion and clockSeqHiAndReserved, so callers are responsible
+// for setting them.
+func FromFields(timeLow uint32, timeMid, timeHiAndVersion uint16, clockSeqHiAndReserved, clockSeqLow byte, node [6]byte) UUID {
+	var u UUID
+	binary.BigEndian.PutUint32(u[0:], timeLow)
+	binary.BigEndian.PutUint16(u[4:], timeMid)
+	binary.BigEndian.PutUint16(u[6:], timeHiAndVersion)
+	u[8] = clockSeqHiAndReserved
+	u[9] = clockSeqLow
+	copy(u[10:], node[:])
+	return u
+}
+
+// FromBytesSuffix returns a UUID read from the last 16 bytes of b, for record
+// formats that append the ID at the end. It will return an error if the slice
+// is shorter than 16 bytes.
+func FromBytesSuffix(b []byte) (UUID, error) {
+	if len(b) < Size {
+		return Nil, fmt.Errorf("uuid: need at least 16 bytes for UUID suffix, got %d bytes", len(b))
+	}
+	return FromBytes(b[len(b)-Size:])
+}
+
+// FromBytesStrict is like FromBytes, but additionally returns an error unless
+// the UUID has the RFC-4122 variant and one of the known versions (1, 3, 4, 5,
+// 6, 7, or 8). This can be used to ensure that stored bytes are genuine UUIDs,
+// rather than an arbitrary 16-byte value.
+func FromBytesStrict(input []byte) (UUID, error) {
+	u, err := FromBytes(input)
+	if err != nil {
+		return Nil, err
+	}
+	if v := u.Variant(); v != VariantRFC4122 {
+		return Nil, fmt.Errorf("uuid: %s has variant %d, not the RFC-4122 variant", u, v)
+	}
+	switch v := u.Version(); v {
+	case 1, 3, 4, 5, 6, 7, 8:
+	default:
+		return Nil, fmt.Errorf("uuid: %s has unknown version %d", u, v)
+	}
+	return u, nil
+}
+
+// FromBytesMaybeLE returns a UUID from 16 raw bytes that may have been stored in
+// Microsoft GUID byte order, where the first three fields (bytes 0-3, 4-5,
+// and 6-7) are little-endian, and reports whether the bytes were swapped into
+// RFC-4122 order. It returns an error if the slice isn't 16 bytes long.
+//
+// The byte order is detected heuristically: the swapped interpretation is
+// only chosen if it has a known version (1, 3, 4, 5, 6, 7, or 8) and the
+// RFC-4122 variant while the bytes as given do not. The variant is in the
+// last eight bytes, which are the same in both orders, so only the version
+// nibble tells them apart. About one in sixteen random values will look valid
+// in both orders, and those are always returned unswapped, so the result
+// should be treated as a best guess for data of unknown provenance.
+func FromBytesMaybeLE(b []byte) (UUID, bool, error) {
+	u, err := FromBytes(b)
+	if err != nil {
+		return Nil, false, err
+	}
+	if isKnownRFC(u) {
+		return u, false, nil
+	}
+
+	le := u
+	le[0], le[1], le[2], le[3] = u[3], u[2], u[1], u[0]
+	le[4], le[5] = u[5], u[4]
+	le[6], le[7] = u[7], u[6]
+	if isKnownRFC(le) {
+		return le, true, nil
+	}
+	return u, false, nil
+}
+
+// isKnownRFC reports whether u has the RFC-4122 variant and one of the known
+// versions.
+func isKnownRFC(u UUID) bool {
+	if u.Variant() != VariantRFC4122 {
+		return false
+	}
+	switch u.Version() {
+	case V1, V3, V4, V5, V6, V7, V8:
+		return true
+	}
+	return false
+}
+
 // FromString returns a UUID parsed from the input string.
 // Input is expected in a form accepted by UnmarshalText.
 func FromString(input string) (UUID, error) {
@@ -63,12 +160,224 @@ func FromStringOrNil(input string) UUID {
 	return uuid
 }
 
+// FromStringNilOnNonRFC returns a UUID parsed from the input string, in a form
+// accepted by UnmarshalText, if it has the RFC-4122 variant or is Nil or Max.
+// Otherwise, including when the input cannot be parsed, Nil is returned. This
+// allows tolerant ingestion of values, such as legacy Microsoft GUIDs, that
+// downstream code assuming RFC-4122 UUIDs cannot handle.
+func FromStringNilOnNonRFC(input string) UUID {
+	u, err := FromString(input)
+	if err != nil {
+		return Nil
+	}
+	if u.Variant() != VariantRFC4122 && u != Max {
+		return Nil
+	}
+	return u
+}
+
+// TryParse returns a UUID parsed from the input string and true, or Nil and
+// false if the input could not be parsed. The input is expected in a form
+// accepted by UnmarshalText.
+func TryParse(input string) (UUID, bool) {
+	u, err := FromString(input)
+	if err != nil {
+		return Nil, false
+	}
+	return u, true
+}
+
+// Format identifies one of the text formats accepted by UnmarshalText.
+type Format byte
+
+// Text formats accepted by UnmarshalText.
+const (
+	FormatUnknown   Format = iota
+	FormatCanonical        // "6ba7b810-9dad-11d1-80b4-00c04fd430c8"
+	FormatHashLike         // "6ba7b8109dad11d180b400c04fd430c8"
+	FormatBraced           // "{6ba7b810-9dad-11d1-80b4-00c04fd430c8}" or "{6ba7b8109dad11d180b400c04fd430c8}"
+	FormatURN              // "urn:uuid:6ba7b810-9dad-11d1-80b4-00c04fd430c8" or "urn:uuid:6ba7b8109dad11d180b400c04fd430c8"
+)
+
+func (f Format) String() string {
+	switch f {
+	case FormatCanonical:
+		return "canonical"
+	case FormatHashLike:
+		return "hash-like"
+	case FormatBraced:
+		return "braced"
+	case FormatURN:
+		return "urn"
+	default:
+		return "unknown"
+	}
+}
+
+// ParseDetect is like FromString, but also returns the Format of the input.
+// If the input cannot be parsed, FormatUnknown is returned along with the
+// error.
+func ParseDetect(input string) (UUID, Format, error) {
+	u, err := FromString(input)
+	if err != nil {
+		return Nil, FormatUnknown, err
+	}
+
+	switch len(input) {
+	case 32:
+		return u, FormatHashLike, nil
+	case 34, 38:
+		return u, FormatBraced, nil
+	case 36:
+		return u, FormatCanonical, nil
+	default: // 41, 45
+		return u, FormatURN, nil
+	}
+}
+
+// ParseAny is the most lenient of the parsing functions, intended for input
+// collected from many sources where the format is not known in advance. It
+// trims surrounding whitespace and a matching pair of surrounding quotes
+// (", ', or `), then accepts the UUID in canonical or hash-like form, in any
+// case, optionally wrapped in braces, prefixed with a case-insensitive
+// "urn:uuid:", or prefixed with "0x". The remaining hex digits are validated
+// the same as FromString.
+func ParseAny(s string) (UUID, error) {
+	t := strings.TrimSpace(s)
+	if len(t) >= 2 {
+		switch q := t[0]; q {
+		case '"', '\'', '`':
+			if t[len(t)-1] == q {
+				t = strings.TrimSpace(t[1 : len(t)-1])
+			}
+		}
+	}
+	if len(t) >= 2 && t[0] == '{' && t[len(t)-1] == '}' {
+		t = t[1 : len(t)-1]
+	}
+	if len(t) >= len(urnPrefix) && strings.EqualFold(t[:len(urnPrefix)], string(urnPrefix)) {
+		t = t[len(urnPrefix):]
+	} else if len(t) >= 2 && t[0] == '0' && (t[1] == 'x' || t[1] == 'X') {
+		t = t[2:]
+	}
+
+	var u UUID
+	if err := u.decodePlain([]byte(t)); err != nil {
+		return Nil, fmt.Errorf("uuid: cannot parse %q: %v", s, err)
+	}
+	return u, nil
+}
+
+// ParseRepairHyphens is like FromString, but also accepts a 36 character string
+// holding 32 hex digits and 4 hyphens in the wrong positions, as produced by
+// some OCR and formatting bugs, by removing the hyphens and parsing the
+// remaining digits. The hex digits themselves are validated as strictly as
+// FromString does.
+func ParseRepairHyphens(s string) (UUID, error) {
+	if len(s) != canonicalLen || strings.Count(s, "-") != 4 {
+		return FromString(s)
+	}
+	if s[8] == '-' && s[13] == '-' && s[18] == '-' && s[23] == '-' {
+		return FromString(s)
+	}
+
+	var u UUID
+	if err := u.decodeHashLike([]byte(strings.Replace(s, "-", "", -1))); err != nil {
+		return Nil, fmt.Errorf("uuid: cannot repair %q: %v", s, err)
+	}
+	return u, nil
+}
+
+// IsCanonical reports whether s is a UUID in the exact form returned by
+// String: 36 characters of lowercase hex digits and dashes, without braces or
+// a URN prefix. It can be used to find stored values that need to be
+// canonicalized.
+func IsCanonical(s string) bool {
+	if len(s) != canonicalLen {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		switch i {
+		case 8, 13, 18, 23:
+			if s[i] != '-' {
+				return false
+			}
+		default:
+			if c := s[i]; !('0' <= c && c <= '9') && !('a' <= c && c <= 'f') {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// canonicalLen is the length of a UUID in its canonical string form.
+const canonicalLen = 36
+
+// ParseConcatenated parses a string consisting of canonical UUID strings
+// concatenated back-to-back without any separator, as found in fixed-width
+// text records. It returns an error if the length of s is not a multiple of
+// 36 or if any of the UUIDs fail to parse.
+func ParseConcatenated(s string) ([]UUID, error) {
+	if len(s)%canonicalLen != 0 {
+		return nil, fmt.Errorf("uuid: length %d of concatenated UUIDs is not a multiple of %d", len(s), canonicalLen)
+	}
+
+	uuids := make([]UUID, len(s)/canonicalLen)
+	for i := range uuids {
+		off := i * canonicalLen
+		if err := uuids[i].decodeCanonical([]byte(s[off : off+canonicalLen])); err != nil {
+			return nil, fmt.Errorf("uuid: invalid UUID at offset %d: %v", off, err)
+		}
+	}
+
+	return uuids, nil
+}
+
 // MarshalText implements the encoding.TextMarshaler interface.
 // The encoding is the same as returned by the String() method.
 func (u UUID) MarshalText() ([]byte, error) {
 	return []byte(u.String()), nil
 }
 
+// UnmarshalJSON implements the json.Unmarshaler interface. In addition to a
+// JSON string in any of the forms accepted by UnmarshalText, it accepts an
+// array of exactly 16 integers in the range [0, 255], which is how some
+// encoders serialize UUIDs as byte lists. A JSON null leaves the UUID
+// unchanged, matching how encoding/json treats null for other types.
+func (u *UUID) UnmarshalJSON(b []byte) error {
+	b = bytes.TrimSpace(b)
+	switch {
+	case bytes.Equal(b, []byte("null")):
+		return nil
+
+	case len(b) > 0 && b[0] == '[':
+		var a []int
+		if err := json.Unmarshal(b, &a); err != nil {
+			return fmt.Errorf("uuid: invalid JSON byte array: %w", err)
+		}
+		if len(a) != Size {
+			return fmt.Errorf("uuid: JSON byte array must have exactly 16 elements, got %d", len(a))
+		}
+		var v UUID
+		for i, n := range a {
+			if n < 0 || n > 0xff {
+				return fmt.Errorf("uuid: JSON byte array element %d out of range: %d", i, n)
+			}
+			v[i] = byte(n)
+		}
+		*u = v
+		return nil
+
+	default:
+		var s string
+		if err := json.Unmarshal(b, &s); err != nil {
+			return fmt.Errorf("uuid: cannot unmarshal %s into a UUID: %w", b, err)
+		}
+		return u.UnmarshalText([]byte(s))
+	}
+}
+
 // UnmarshalText implements the encoding.TextUnmarshaler interface.
 // Following formats are supported:
 //
@@ -104,6 +413,13 @@ func (u UUID) MarshalText() ([]byte, error) {
 //   urn := URN ':' UUID-NID ':' plain
 //
 func (u *UUID) UnmarshalText(text []byte) error {
+	// None of the longer formats start with a canonical UUID, so this also
+	// catches trailing data that happens to give them a valid length. The
+	// dash check keeps valid braced and URN strings from allocating.
+	if len(text) > canonicalLen && text[8] == '-' && IsCanonical(strings.ToLower(string(text[:canonicalLen]))) {
+		return fmt.Errorf("uuid: unexpected trailing data after UUID at offset %d in string %q", canonicalLen, text)
+	}
+
 	switch len(text) {
 	case 32:
 		return u.decodeHashLike(text)
@@ -156,6 +472,10 @@ func (u *UUID) decodeHashLike(t []byte) error {
 // decodeBraced decodes UUID strings that are using the following formats:
 //  "{6ba7b810-9dad-11d1-80b4-00c04fd430c8}"
 //  "{6ba7b8109dad11d180b400c04fd430c8}".
+//
+// Hex digits may be of either case, which covers the uppercase braced
+// hash-like form that SQL Server uniqueidentifier values are sometimes
+// round-tripped as.
 func (u *UUID) decodeBraced(t []byte) error {
 	l := len(t)
 
@@ -210,3 +530,102 @@ func (u *UUID) UnmarshalBinary(data []byte) error {
 
 	return nil
 }
+
+// ReadBinary reads the next 16 bytes from r into u, making it suitable for
+// decoding streams of packed binary UUIDs without allocating. It returns
+// io.EOF if no bytes were read and io.ErrUnexpectedEOF if r was exhausted
+// after a partial read.
+func ReadBinary(r io.Reader, u *UUID) error {
+	_, err := io.ReadFull(r, u[:])
+	return err
+}
+
+// maxStreamToken is the longest token ValidateStream keeps, which is more
+// than any format accepted by FromString.
+const maxStreamToken = 64
+
+// ValidateStream reads whitespace separated tokens from r, such as an export
+// of UUIDs one per line, and counts how many parse as UUIDs with FromString
+// and how many do not. Tokens are read one at a time, so arbitrarily large
+// inputs can be validated without loading them into memory, and a token too
+// long to be a UUID is counted as invalid rather than stopping the read.
+// firstErr is the parse error of the first invalid token, which includes the
+// token itself, or the error that stopped reading r, if any.
+func ValidateStream(r io.Reader) (valid, invalid int, firstErr error) {
+	br := bufio.NewReader(r)
+	tok := make([]byte, 0, maxStreamToken)
+	n := 0 // length of the current token, of which tok holds the start
+	check := func() {
+		var err error
+		if n > maxStreamToken {
+			err = fmt.Errorf("uuid: token %q... is %d bytes long", tok, n)
+		} else {
+			var u UUID
+			err = u.UnmarshalText(tok)
+		}
+		if err != nil {
+			if firstErr == nil {
+				firstErr = fmt.Errorf("uuid: invalid UUID token %d: %v", valid+invalid+1, err)
+			}
+			invalid++
+		} else {
+			valid++
+		}
+		tok, n = tok[:0], 0
+	}
+	for {
+		c, err := br.ReadByte()
+		if err != nil {
+			if n > 0 {
+				check()
+			}
+			if err != io.EOF && firstErr == nil {
+				firstErr = err
+			}
+			return valid, invalid, firstErr
+		}
+		r, size := rune(c), 1
+		if c >= utf8.RuneSelf {
+			br.UnreadByte()
+			r, size, _ = br.ReadRune() // c is buffered, so this cannot fail
+		}
+		if unicode.IsSpace(r) {
+			if n > 0 {
+				check()
+			}
+			continue
+		}
+		if n+size <= maxStreamToken {
+			if size == 1 {
+				tok = append(tok, c)
+			} else {
+				var buf [utf8.UTFMax]byte
+				tok = append(tok, buf[:utf8.EncodeRune(buf[:], r)]...)
+			}
+		}
+		n += size
+	}
+}
+
+// ParseLimited reads the text form of a UUID from r, in any format accepted by
+// UnmarshalText, reading at most max bytes. It returns an error without
+// reading further if r holds more than max bytes, which protects parsers of
+// untrusted streams from unbounded input. Since the longest accepted format is
+// 45 bytes, a larger max has no effect on what can be parsed.
+func ParseLimited(r io.Reader, max int) (UUID, error) {
+	if max < 0 {
+		return Nil, fmt.Errorf("uuid: invalid input limit %d", max)
+	}
+	b, err := ioutil.ReadAll(io.LimitReader(r, int64(max)+1))
+	if err != nil {
+		return Nil, err
+	}
+	if len(b) > max {
+		return Nil, fmt.Errorf("uuid: input exceeds limit of %d bytes", max)
+	}
+	var u UUID
+	if err := u.UnmarshalText(b); err != nil {
+		return Nil, err
+	}
+	return u, nil
+}
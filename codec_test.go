@@ -23,11 +23,15 @@ package uuid
 
 import (
 	"bytes"
+	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -84,6 +88,79 @@ func TestFromBytesOrNil(t *testing.T) {
 
 }
 
+func TestFromBytesSuffix(t *testing.T) {
+	record := append([]byte("header\x00\x01"), codecTestData...)
+	for _, b := range [][]byte{record, codecTestData} {
+		got, err := FromBytesSuffix(b)
+		if err != nil {
+			t.Fatalf("FromBytesSuffix(%x): %v", b, err)
+		}
+		if got != codecTestUUID {
+			t.Errorf("FromBytesSuffix(%x) = %v, want %v", b, got, codecTestUUID)
+		}
+	}
+	for _, b := range [][]byte{nil, codecTestData[:15]} {
+		_, err := FromBytesSuffix(b)
+		testErrCheck(t, fmt.Sprintf("FromBytesSuffix(%x)", b), "need at least 16 bytes", err)
+	}
+}
+
+func TestFromFields(t *testing.T) {
+	node := [6]byte{0x00, 0xc0, 0x4f, 0xd4, 0x30, 0xc8}
+	u := FromFields(0x6ba7b810, 0x9dad, 0x11d1, 0x80, 0xb4, node)
+	if u != codecTestUUID {
+		t.Errorf("FromFields() = %v, want %v", u, codecTestUUID)
+	}
+	if v := u.Version(); v != V1 {
+		t.Errorf("%v.Version() = %d, want %d", u, v, V1)
+	}
+	if v := u.Variant(); v != VariantRFC4122 {
+		t.Errorf("%v.Variant() = %d, want %d", u, v, VariantRFC4122)
+	}
+	if !bytes.Equal(u[10:], node[:]) {
+		t.Errorf("%v node = %x, want %x", u, u[10:], node)
+	}
+}
+
+func TestFromBytesStrict(t *testing.T) {
+	t.Run("Valid", func(t *testing.T) {
+		for _, v := range []byte{1, 3, 4, 5, 6, 7, 8} {
+			want := codecTestUUID
+			want.SetVersion(v)
+			got, err := FromBytesStrict(want.Bytes())
+			if err != nil {
+				t.Fatalf("FromBytesStrict(%x): %v", want.Bytes(), err)
+			}
+			if got != want {
+				t.Errorf("FromBytesStrict(%x) = %v, want %v", want.Bytes(), got, want)
+			}
+		}
+	})
+	t.Run("Invalid", func(t *testing.T) {
+		invalid := [][]byte{
+			codecTestData[:15],
+			Nil.Bytes(),
+			{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff},
+		}
+		for _, v := range []byte{0, 2, 9, 15} {
+			u := codecTestUUID
+			u.SetVersion(v)
+			invalid = append(invalid, u.Bytes())
+		}
+		for _, variant := range []byte{VariantNCS, VariantMicrosoft, VariantFuture} {
+			u := codecTestUUID
+			u.SetVariant(variant)
+			invalid = append(invalid, u.Bytes())
+		}
+		for _, b := range invalid {
+			got, err := FromBytesStrict(b)
+			if err == nil {
+				t.Errorf("FromBytesStrict(%x): want err != nil, got %v", b, got)
+			}
+		}
+	})
+}
+
 type fromStringTest struct {
 	input   string
 	variant string
@@ -168,6 +245,38 @@ var invalidFromStringInputs = []string{
 	"6ba7b8109dad11d180b4-00c04fd430c8",
 }
 
+func TestFromBytesMaybeLE(t *testing.T) {
+	le := []byte{0x10, 0xb8, 0xa7, 0x6b, 0xad, 0x9d, 0xd1, 0x11, 0x80, 0xb4, 0x00, 0xc0, 0x4f, 0xd4, 0x30, 0xc8}
+	v4 := Must(FromString("919108f7-52d1-4320-9bac-f847db4148a8"))
+	// valid V4 as given, and also V3 once swapped
+	ambiguous := Must(FromString("919108f7-52d1-4330-9bac-f847db4148a8"))
+
+	tests := []struct {
+		b       []byte
+		want    UUID
+		swapped bool
+	}{
+		{b: le, want: codecTestUUID, swapped: true},
+		{b: codecTestData, want: codecTestUUID, swapped: false},
+		{b: v4.Bytes(), want: v4, swapped: false},
+		{b: ambiguous.Bytes(), want: ambiguous, swapped: false},
+		{b: Max.Bytes(), want: Max, swapped: false},
+	}
+	for _, tt := range tests {
+		got, swapped, err := FromBytesMaybeLE(tt.b)
+		if err != nil {
+			t.Fatalf("FromBytesMaybeLE(%x): %v", tt.b, err)
+		}
+		if got != tt.want || swapped != tt.swapped {
+			t.Errorf("FromBytesMaybeLE(%x) = %v, %t, want %v, %t", tt.b, got, swapped, tt.want, tt.swapped)
+		}
+	}
+
+	if _, _, err := FromBytesMaybeLE(le[:15]); err == nil {
+		t.Errorf("FromBytesMaybeLE(%x): want error", le[:15])
+	}
+}
+
 func TestFromString(t *testing.T) {
 	t.Run("Valid", func(t *testing.T) {
 		for _, fst := range fromStringTests {
@@ -201,6 +310,291 @@ func TestFromStringOrNil(t *testing.T) {
 	})
 }
 
+func TestFromStringSQLServerBraced(t *testing.T) {
+	// SQL Server uniqueidentifier values sometimes round-trip as uppercase
+	// hash-like strings inside braces.
+	in := "{6BA7B8109DAD11D180B400C04FD430C8}"
+	u, err := FromString(in)
+	if err != nil {
+		t.Fatalf("FromString(%q): %v", in, err)
+	}
+	if u != codecTestUUID {
+		t.Errorf("FromString(%q) = %v, want %v", in, u, codecTestUUID)
+	}
+}
+
+func TestFromStringNilOnNonRFC(t *testing.T) {
+	tests := []struct {
+		input string
+		want  UUID
+	}{
+		{input: "6ba7b810-9dad-11d1-80b4-00c04fd430c8", want: codecTestUUID},
+		{input: "00000000-0000-0000-0000-000000000000", want: Nil},
+		{input: "ffffffff-ffff-ffff-ffff-ffffffffffff", want: Max},
+		{input: "6ba7b810-9dad-11d1-c0b4-00c04fd430c8", want: Nil}, // Microsoft
+		{input: "6ba7b810-9dad-11d1-00b4-00c04fd430c8", want: Nil}, // NCS
+		{input: "6ba7b810-9dad-11d1-80b4-00c04fd430c", want: Nil},
+	}
+	for _, tt := range tests {
+		if got := FromStringNilOnNonRFC(tt.input); got != tt.want {
+			t.Errorf("FromStringNilOnNonRFC(%q) = %v, want %v", tt.input, got, tt.want)
+		}
+	}
+	for _, s := range invalidFromStringInputs {
+		if got := FromStringNilOnNonRFC(s); got != Nil {
+			t.Errorf("FromStringNilOnNonRFC(%q) = %v, want %v", s, got, Nil)
+		}
+	}
+}
+
+func TestFromStringTrailingData(t *testing.T) {
+	inputs := []string{
+		"6ba7b810-9dad-11d1-80b4-00c04fd430c8x",
+		"6ba7b810-9dad-11d1-80b4-00c04fd430c8\n",
+		"6BA7B810-9DAD-11D1-80B4-00C04FD430C8,",
+		"6ba7b810-9dad-11d1-80b4-00c04fd430c8 trailing garbage",
+		// lengths of the braced and URN formats
+		"6ba7b810-9dad-11d1-80b4-00c04fd430c8xx",
+		"6ba7b810-9dad-11d1-80b4-00c04fd430c8xxxxx",
+		"6ba7b810-9dad-11d1-80b4-00c04fd430c8xxxxxxxxx",
+	}
+	for _, s := range inputs {
+		_, err := FromString(s)
+		testErrCheck(t, fmt.Sprintf("FromString(%q)", s), "unexpected trailing data after UUID at offset 36", err)
+	}
+
+	// near misses without a valid canonical prefix keep the length error
+	for _, s := range []string{"6ba7b810-9dad-11d1-80b4-00c04fd430cxx", "6ba7b8109dad11d180b400c04fd430c8x"} {
+		_, err := FromString(s)
+		testErrCheck(t, fmt.Sprintf("FromString(%q)", s), "incorrect UUID length", err)
+	}
+}
+
+func TestTryParse(t *testing.T) {
+	t.Run("Valid", func(t *testing.T) {
+		for _, fst := range fromStringTests {
+			got, ok := TryParse(fst.input)
+			if !ok || got != codecTestUUID {
+				t.Errorf("TryParse(%q) = %v, %t, want %v, true", fst.input, got, ok, codecTestUUID)
+			}
+		}
+	})
+	t.Run("Invalid", func(t *testing.T) {
+		for _, s := range invalidFromStringInputs {
+			got, ok := TryParse(s)
+			if ok || got != Nil {
+				t.Errorf("TryParse(%q) = %v, %t, want Nil, false", s, got, ok)
+			}
+		}
+	})
+}
+
+func TestParseDetect(t *testing.T) {
+	want := map[string]Format{
+		"Canonical":       FormatCanonical,
+		"BracedCanonical": FormatBraced,
+		"BracedHashlike":  FormatBraced,
+		"Hashlike":        FormatHashLike,
+		"URNCanonical":    FormatURN,
+		"URNHashlike":     FormatURN,
+	}
+	for _, fst := range fromStringTests {
+		u, f, err := ParseDetect(fst.input)
+		if err != nil {
+			t.Fatalf("ParseDetect(%q): %v", fst.input, err)
+		}
+		if u != codecTestUUID {
+			t.Errorf("ParseDetect(%q) = %v, want %v", fst.input, u, codecTestUUID)
+		}
+		if f != want[fst.variant] {
+			t.Errorf("ParseDetect(%q) format = %v, want %v", fst.input, f, want[fst.variant])
+		}
+	}
+	for _, s := range invalidFromStringInputs {
+		u, f, err := ParseDetect(s)
+		if err == nil || u != Nil || f != FormatUnknown {
+			t.Errorf("ParseDetect(%q) = %v, %v, %v, want error", s, u, f, err)
+		}
+	}
+}
+
+func TestParseAny(t *testing.T) {
+	t.Run("Valid", func(t *testing.T) {
+		for _, fst := range fromStringTests {
+			u, err := ParseAny(fst.input)
+			if err != nil {
+				t.Fatalf("ParseAny(%q): %v", fst.input, err)
+			}
+			if u != codecTestUUID {
+				t.Errorf("ParseAny(%q) = %v, want %v", fst.input, u, codecTestUUID)
+			}
+		}
+
+		inputs := []string{
+			"  6ba7b810-9dad-11d1-80b4-00c04fd430c8\n",
+			"6BA7B810-9DAD-11D1-80B4-00C04FD430C8",
+			`"6ba7b810-9dad-11d1-80b4-00c04fd430c8"`,
+			"'{6ba7b810-9dad-11d1-80b4-00c04fd430c8}'",
+			"`6ba7b8109dad11d180b400c04fd430c8`",
+			"\t\" 6ba7b810-9dad-11d1-80b4-00c04fd430c8 \"",
+			"URN:UUID:6ba7b810-9dad-11d1-80b4-00c04fd430c8",
+			"Urn:Uuid:6BA7B8109DAD11D180B400C04FD430C8",
+			"{urn:uuid:6ba7b810-9dad-11d1-80b4-00c04fd430c8}",
+			"0x6ba7b8109dad11d180b400c04fd430c8",
+			"0X6BA7B8109DAD11D180B400C04FD430C8",
+			"{0x6ba7b8109dad11d180b400c04fd430c8}",
+		}
+		for _, s := range inputs {
+			u, err := ParseAny(s)
+			if err != nil {
+				t.Fatalf("ParseAny(%q): %v", s, err)
+			}
+			if u != codecTestUUID {
+				t.Errorf("ParseAny(%q) = %v, want %v", s, u, codecTestUUID)
+			}
+		}
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		inputs := []string{
+			"",
+			"   ",
+			`""`,
+			"not a uuid",
+			"\"6ba7b810-9dad-11d1-80b4-00c04fd430c8'",
+			"{6ba7b810-9dad-11d1-80b4-00c04fd430c8",
+			"6ba7b810-9dad-11d1-80b4-00c04fd430cz",
+			"0x6ba7b810-9dad-11d1-80b4-00c04fd430c",
+			"urn:uuid:",
+		}
+		for _, s := range inputs {
+			if u, err := ParseAny(s); err == nil {
+				t.Errorf("ParseAny(%q) = %v, want error", s, u)
+			}
+		}
+	})
+}
+
+func TestParseRepairHyphens(t *testing.T) {
+	valid := []string{
+		"6ba7b810-9dad-11d1-80b4-00c04fd430c8",
+		"6ba7b8109-dad-11d1-80b4-00c04fd430c8",
+		"6ba7-b8109dad-11d180b4-00c0-4fd430c8",
+		"----6ba7b8109dad11d180b400c04fd430c8",
+		"6ba7b8109dad11d180b400c04fd430c8",
+		"urn:uuid:6ba7b810-9dad-11d1-80b4-00c04fd430c8",
+	}
+	for _, s := range valid {
+		u, err := ParseRepairHyphens(s)
+		if err != nil {
+			t.Fatalf("ParseRepairHyphens(%q): %v", s, err)
+		}
+		if u != codecTestUUID {
+			t.Errorf("ParseRepairHyphens(%q) = %v, want %v", s, u, codecTestUUID)
+		}
+	}
+
+	invalid := []string{
+		"6ba7b8109-dad-11d1-80b4-00c04fd430cz",  // bad hex
+		"6ba7b8109-dad-11d1-80b4-00c04fd430c8-", // too long
+		"6ba7b8109-dad-11d1-80b4-00c04fd43-c8-", // too many hyphens
+		"6ba7b8109dad-11d1-80b4-00c04fd430c8",   // too short
+		"6ba7b8109dad-11d1-80b4-00c04fd430c8x",  // three hyphens
+	}
+	for _, s := range invalid {
+		if u, err := ParseRepairHyphens(s); err == nil {
+			t.Errorf("ParseRepairHyphens(%q) = %v, want error", s, u)
+		}
+	}
+}
+
+func TestFormat_String(t *testing.T) {
+	tests := []struct {
+		f    Format
+		want string
+	}{
+		{f: FormatCanonical, want: "canonical"},
+		{f: FormatHashLike, want: "hash-like"},
+		{f: FormatBraced, want: "braced"},
+		{f: FormatURN, want: "urn"},
+		{f: FormatUnknown, want: "unknown"},
+		{f: 0xff, want: "unknown"},
+	}
+	for _, tt := range tests {
+		if got := tt.f.String(); got != tt.want {
+			t.Errorf("Format(%d).String() = %q, want %q", tt.f, got, tt.want)
+		}
+	}
+}
+
+func TestIsCanonical(t *testing.T) {
+	tests := []struct {
+		s    string
+		want bool
+	}{
+		{s: "6ba7b810-9dad-11d1-80b4-00c04fd430c8", want: true},
+		{s: "00000000-0000-0000-0000-000000000000", want: true},
+		{s: "6BA7B810-9DAD-11D1-80B4-00C04FD430C8", want: false},
+		{s: "6ba7b810-9dad-11d1-80b4-00c04fd430C8", want: false},
+		{s: "{6ba7b810-9dad-11d1-80b4-00c04fd430c8}", want: false},
+		{s: "urn:uuid:6ba7b810-9dad-11d1-80b4-00c04fd430c8", want: false},
+		{s: "6ba7b8109dad11d180b400c04fd430c8", want: false},
+		{s: "", want: false},
+	}
+	for _, s := range invalidFromStringInputs {
+		tests = append(tests, struct {
+			s    string
+			want bool
+		}{s: s, want: false})
+	}
+	for _, tt := range tests {
+		if got := IsCanonical(tt.s); got != tt.want {
+			t.Errorf("IsCanonical(%q) = %t, want %t", tt.s, got, tt.want)
+		}
+	}
+}
+
+func TestParseConcatenated(t *testing.T) {
+	t.Run("Valid", func(t *testing.T) {
+		want := []UUID{NamespaceDNS, NamespaceURL, NamespaceOID}
+		s := NamespaceDNS.String() + NamespaceURL.String() + NamespaceOID.String()
+		got, err := ParseConcatenated(s)
+		if err != nil {
+			t.Fatalf("ParseConcatenated(%q): %v", s, err)
+		}
+		if len(got) != len(want) {
+			t.Fatalf("ParseConcatenated(%q) returned %d UUIDs, want %d", s, len(got), len(want))
+		}
+		for i := range want {
+			if got[i] != want[i] {
+				t.Errorf("ParseConcatenated(%q)[%d] = %v, want %v", s, i, got[i], want[i])
+			}
+		}
+	})
+	t.Run("Empty", func(t *testing.T) {
+		got, err := ParseConcatenated("")
+		if err != nil {
+			t.Fatalf("ParseConcatenated(\"\"): %v", err)
+		}
+		if len(got) != 0 {
+			t.Errorf("ParseConcatenated(\"\") = %v, want no UUIDs", got)
+		}
+	})
+	t.Run("Invalid", func(t *testing.T) {
+		invalid := []string{
+			NamespaceDNS.String() + NamespaceURL.String()[:35],
+			NamespaceDNS.String() + "6ba7b8109dad11d180b400c04fd430c80000",
+			NamespaceDNS.String() + "zba7b810-9dad-11d1-80b4-00c04fd430c8",
+		}
+		for _, s := range invalid {
+			got, err := ParseConcatenated(s)
+			if err == nil {
+				t.Errorf("ParseConcatenated(%q): want err != nil, got %v", s, got)
+			}
+		}
+	})
+}
+
 func TestMarshalBinary(t *testing.T) {
 	got, err := codecTestUUID.MarshalBinary()
 	if err != nil {
@@ -211,6 +605,200 @@ func TestMarshalBinary(t *testing.T) {
 	}
 }
 
+func TestReadBinary(t *testing.T) {
+	t.Run("Valid", func(t *testing.T) {
+		want := []UUID{NamespaceDNS, NamespaceURL, NamespaceOID, NamespaceX500}
+		var buf bytes.Buffer
+		for _, u := range want {
+			buf.Write(u.Bytes())
+		}
+		for i, w := range want {
+			var u UUID
+			if err := ReadBinary(&buf, &u); err != nil {
+				t.Fatalf("ReadBinary() #%d: %v", i, err)
+			}
+			if u != w {
+				t.Errorf("ReadBinary() #%d = %v, want %v", i, u, w)
+			}
+		}
+		var u UUID
+		if err := ReadBinary(&buf, &u); err != io.EOF {
+			t.Errorf("ReadBinary() at end of input = %v, want %v", err, io.EOF)
+		}
+	})
+	t.Run("Short", func(t *testing.T) {
+		var u UUID
+		r := bytes.NewReader(codecTestData[:10])
+		if err := ReadBinary(r, &u); err != io.ErrUnexpectedEOF {
+			t.Errorf("ReadBinary() on short input = %v, want %v", err, io.ErrUnexpectedEOF)
+		}
+	})
+}
+
+func TestValidateStream(t *testing.T) {
+	in := "6ba7b810-9dad-11d1-80b4-00c04fd430c8\n" +
+		"not-a-uuid\n" +
+		"\t{6ba7b810-9dad-11d1-80b4-00c04fd430c8}  6ba7b8109dad11d180b400c04fd430c8\r\n" +
+		"\n" +
+		"6ba7b810-9dad-11d1-80b4-00c04fd430cz\n" +
+		"urn:uuid:6ba7b810-9dad-11d1-80b4-00c04fd430c8"
+
+	valid, invalid, err := ValidateStream(strings.NewReader(in))
+	if valid != 4 || invalid != 2 {
+		t.Errorf("ValidateStream() = %d valid, %d invalid, want %d valid, %d invalid", valid, invalid, 4, 2)
+	}
+	testErrCheck(t, "ValidateStream()", "not-a-uuid", err)
+	testErrCheck(t, "ValidateStream()", "token 2", err)
+
+	valid, invalid, err = ValidateStream(strings.NewReader(""))
+	if valid != 0 || invalid != 0 || err != nil {
+		t.Errorf("ValidateStream(\"\") = %d, %d, %v, want 0, 0, <nil>", valid, invalid, err)
+	}
+
+	r := io.MultiReader(strings.NewReader(codecTestUUID.String()+"\n"), &faultyReader{})
+	valid, invalid, err = ValidateStream(r)
+	if valid != 1 || invalid != 0 || err == nil {
+		t.Errorf("ValidateStream(faultyReader) = %d, %d, %v, want 1, 0, error", valid, invalid, err)
+	}
+
+	// a token longer than any buffer only counts as invalid
+	long := strings.Repeat("0", 1<<20)
+	in = codecTestUUID.String() + "\n" + long + "\n" + codecTestUUID.String() + " " + long
+	valid, invalid, err = ValidateStream(strings.NewReader(in))
+	if valid != 2 || invalid != 2 {
+		t.Errorf("ValidateStream(long tokens) = %d valid, %d invalid, want %d valid, %d invalid", valid, invalid, 2, 2)
+	}
+	testErrCheck(t, "ValidateStream(long tokens)", "token 2", err)
+	testErrCheck(t, "ValidateStream(long tokens)", "1048576 bytes long", err)
+}
+
+func TestParseLimited(t *testing.T) {
+	in := "urn:uuid:6ba7b810-9dad-11d1-80b4-00c04fd430c8"
+
+	t.Run("AtLimit", func(t *testing.T) {
+		u, err := ParseLimited(strings.NewReader(in), len(in))
+		if err != nil {
+			t.Fatalf("ParseLimited(%q, %d): %v", in, len(in), err)
+		}
+		if u != codecTestUUID {
+			t.Errorf("ParseLimited(%q, %d) = %v, want %v", in, len(in), u, codecTestUUID)
+		}
+	})
+	t.Run("BelowLimit", func(t *testing.T) {
+		u, err := ParseLimited(strings.NewReader(in), 1024)
+		if err != nil {
+			t.Fatalf("ParseLimited(%q, %d): %v", in, 1024, err)
+		}
+		if u != codecTestUUID {
+			t.Errorf("ParseLimited(%q, %d) = %v, want %v", in, 1024, u, codecTestUUID)
+		}
+	})
+	t.Run("AboveLimit", func(t *testing.T) {
+		r := strings.NewReader(in)
+		_, err := ParseLimited(r, 36)
+		testErrCheck(t, "ParseLimited()", "exceeds limit", err)
+		if n := len(in) - r.Len(); n != 37 {
+			t.Errorf("ParseLimited() read %d bytes, want %d", n, 37)
+		}
+	})
+	t.Run("Unbounded", func(t *testing.T) {
+		_, err := ParseLimited(zeroReader{}, 64)
+		testErrCheck(t, "ParseLimited()", "exceeds limit", err)
+	})
+	t.Run("Invalid", func(t *testing.T) {
+		for _, s := range invalidFromStringInputs {
+			if u, err := ParseLimited(strings.NewReader(s), 64); err == nil {
+				t.Errorf("ParseLimited(%q) = %v, want error", s, u)
+			}
+		}
+		if _, err := ParseLimited(strings.NewReader(in), -1); err == nil {
+			t.Error("ParseLimited() with a negative limit: want error")
+		}
+	})
+}
+
+// zeroReader is an endless stream of zero bytes.
+type zeroReader struct{}
+
+func (zeroReader) Read(p []byte) (int, error) {
+	for i := range p {
+		p[i] = 0
+	}
+	return len(p), nil
+}
+
+func TestUnmarshalJSON(t *testing.T) {
+	t.Run("String", func(t *testing.T) {
+		var u UUID
+		if err := json.Unmarshal([]byte(`"6ba7b810-9dad-11d1-80b4-00c04fd430c8"`), &u); err != nil {
+			t.Fatal(err)
+		}
+		if u != codecTestUUID {
+			t.Errorf("json.Unmarshal() = %v, want %v", u, codecTestUUID)
+		}
+	})
+	t.Run("ByteArray", func(t *testing.T) {
+		in := `[107, 167, 184, 16, 157, 173, 17, 209, 128, 180, 0, 192, 79, 212, 48, 200]`
+		var u UUID
+		if err := json.Unmarshal([]byte(in), &u); err != nil {
+			t.Fatal(err)
+		}
+		if u != codecTestUUID {
+			t.Errorf("json.Unmarshal(%s) = %v, want %v", in, u, codecTestUUID)
+		}
+	})
+	t.Run("Null", func(t *testing.T) {
+		u := codecTestUUID
+		if err := json.Unmarshal([]byte("null"), &u); err != nil {
+			t.Fatal(err)
+		}
+		if u != codecTestUUID {
+			t.Errorf("json.Unmarshal(null) changed UUID to %v", u)
+		}
+	})
+	t.Run("Invalid", func(t *testing.T) {
+		inputs := []string{
+			`[107, 167, 184, 16, 157, 173, 17, 209, 128, 180, 0, 192, 79, 212, 48]`,
+			`[107, 167, 184, 16, 157, 173, 17, 209, 128, 180, 0, 192, 79, 212, 48, 200, 1]`,
+			`[107, 167, 184, 16, 157, 173, 17, 209, 128, 180, 0, 192, 79, 212, 48, 256]`,
+			`[107, 167, 184, 16, 157, 173, 17, 209, 128, 180, 0, 192, 79, 212, 48, -1]`,
+			`[107, 167, 184, 16, 157, 173, 17, 209, 128, 180, 0, 192, 79, 212, 48, 2.5]`,
+			`["6b", 167, 184, 16, 157, 173, 17, 209, 128, 180, 0, 192, 79, 212, 48, 200]`,
+			`"6ba7b810-9dad-11d1-80b4-00c04fd430c"`,
+			`42`,
+			`{}`,
+		}
+		for _, in := range inputs {
+			var u UUID
+			if err := json.Unmarshal([]byte(in), &u); err == nil {
+				t.Errorf("json.Unmarshal(%s) = %v, want error", in, u)
+			}
+		}
+	})
+	t.Run("WrapsError", func(t *testing.T) {
+		for _, in := range []string{`42`, `[1, "2"]`} {
+			var u UUID
+			err := u.UnmarshalJSON([]byte(in))
+			var typeErr *json.UnmarshalTypeError
+			if !errors.As(err, &typeErr) {
+				t.Errorf("UnmarshalJSON(%s) = %v, want it to wrap a *json.UnmarshalTypeError", in, err)
+				continue
+			}
+			testErrCheck(t, "UnmarshalJSON()", typeErr.Error(), err)
+		}
+	})
+	t.Run("NullUUID", func(t *testing.T) {
+		in := `[107, 167, 184, 16, 157, 173, 17, 209, 128, 180, 0, 192, 79, 212, 48, 200]`
+		var u NullUUID
+		if err := json.Unmarshal([]byte(in), &u); err != nil {
+			t.Fatal(err)
+		}
+		if !u.Valid || u.UUID != codecTestUUID {
+			t.Errorf("json.Unmarshal(%s) = %+v, want %v", in, u, codecTestUUID)
+		}
+	})
+}
+
 func TestMarshalText(t *testing.T) {
 	want := []byte("6ba7b810-9dad-11d1-80b4-00c04fd430c8")
 	got, err := codecTestUUID.MarshalText()
@@ -0,0 +1,86 @@
+// Copyright (C) 2013-2018 by Maxim Bublis <b@codemonkey.ru>
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the
+// "Software"), to deal in the Software without restriction, including
+// without limitation the rights to use, copy, modify, merge, publish,
+// distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to
+// the following conditions:
+//
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+// MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION
+// WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package uuid
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+// compareSQLServer compares the node and clock sequence fields of a and b in
+// the order used by SQL Server for uniqueidentifier values. The remaining
+// fields are ignored, they are random in COMB UUIDs.
+func compareSQLServer(a, b UUID) int {
+	if c := bytes.Compare(a[10:], b[10:]); c != 0 {
+		return c
+	}
+	return bytes.Compare(a[8:10], b[8:10])
+}
+
+func TestNewCOMB(t *testing.T) {
+	now := time.Date(2021, 11, 26, 12, 34, 56, 789123456, time.UTC)
+	g := NewGenWithOptions(WithEpochFunc(func() time.Time { return now }))
+
+	var prev UUID
+	for i := 0; i < 100; i++ {
+		u, err := g.NewCOMB()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if u.Version() != V8 || u.Variant() != VariantRFC4122 {
+			t.Fatalf("%v has version %d and variant %d", u, u.Version(), u.Variant())
+		}
+		ts, err := TimeFromCOMB(u)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := now.Truncate(time.Millisecond); !ts.Equal(want) {
+			t.Errorf("TimeFromCOMB(%v) = %v, want %v", u, ts, want)
+		}
+		if i > 0 && compareSQLServer(prev, u) >= 0 {
+			t.Errorf("COMB UUIDs %v and %v do not sort in SQL Server order", prev, u)
+		}
+		prev = u
+		now = now.Add(time.Millisecond)
+	}
+
+	u, err := NewCOMB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ts, _ := TimeFromCOMB(u); time.Since(ts) > time.Minute {
+		t.Errorf("TimeFromCOMB(%v) = %v, want about now", u, ts)
+	}
+
+	if _, err := TimeFromCOMB(Must(NewV4())); err == nil {
+		t.Error("TimeFromCOMB() of a V4 UUID: want error")
+	}
+	now = time.Unix(-1, 0)
+	if _, err := g.NewCOMB(); err == nil {
+		t.Error("NewCOMB() before 1970: want error")
+	}
+	g = NewGenWithOptions(WithRandomReader(&faultyReader{}))
+	if _, err := g.NewCOMB(); err == nil {
+		t.Error("NewCOMB() with a faulty reader: want error")
+	}
+}
@@ -0,0 +1,284 @@
+// Copyright (C) 2013-2018 by Maxim Bublis <b@codemonkey.ru>
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the
+// "Software"), to deal in the Software without restriction, including
+// without limitation the rights to use, copy, modify, merge, publish,
+// distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to
+// the following conditions:
+//
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+// MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION
+// WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package uuid
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// cursorAlphabet is a URL-safe base64 alphabet arranged in ascending ASCII
+// order, so that the lexicographic order of encoded values matches the order
+// of the bytes they encode.
+const cursorAlphabet = "-0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZ_abcdefghijklmnopqrstuvwxyz"
+
+var cursorEncoding = base64.NewEncoding(cursorAlphabet).WithPadding(base64.NoPadding).Strict()
+
+// cursorLen is the length of an encoded cursor.
+var cursorLen = cursorEncoding.EncodedLen(Size)
+
+// Cursor returns an opaque, URL-safe token for the UUID that is suitable for
+// use as a pagination cursor.
+//
+// Cursors sort lexicographically in the same order as the UUIDs they encode,
+// which for time-ordered versions such as V6 and V7 means they can be used
+// directly for keyset pagination.
+func (u UUID) Cursor() string {
+	return cursorEncoding.EncodeToString(u[:])
+}
+
+// CursorParse decodes a cursor returned by Cursor back into a UUID.
+func CursorParse(s string) (UUID, error) {
+	var u UUID
+	if len(s) != cursorLen {
+		return Nil, fmt.Errorf("uuid: incorrect cursor length %d in string %q", len(s), s)
+	}
+	if _, err := cursorEncoding.Decode(u[:], []byte(s)); err != nil {
+		return Nil, fmt.Errorf("uuid: invalid cursor %q: %v", s, err)
+	}
+	return u, nil
+}
+
+// GroupedHex returns the UUID as eight colon-separated groups of four hex
+// digits, in the style of an IPv6 address:
+// xxxx:xxxx:xxxx:xxxx:xxxx:xxxx:xxxx:xxxx. This is intended for display only.
+func (u UUID) GroupedHex() string {
+	return string(u.appendGrouped(make([]byte, 0, 39), ':'))
+}
+
+// appendGrouped appends u to dst as eight groups of four hex digits separated
+// by sep.
+func (u UUID) appendGrouped(dst []byte, sep byte) []byte {
+	var buf [4]byte
+	for i := 0; i < Size; i += 2 {
+		if i > 0 {
+			dst = append(dst, sep)
+		}
+		hex.Encode(buf[:], u[i:i+2])
+		dst = append(dst, buf[:]...)
+	}
+	return dst
+}
+
+// DottedGroups returns the UUID as eight dot-separated groups of four hex
+// digits, xxxx.xxxx.xxxx.xxxx.xxxx.xxxx.xxxx.xxxx, as used by some legacy
+// mainframe exports. FromDottedGroups reverses the conversion.
+func (u UUID) DottedGroups() string {
+	return string(u.appendGrouped(make([]byte, 0, 39), '.'))
+}
+
+// FromDottedGroups parses a UUID in the layout returned by DottedGroups. The
+// input must consist of exactly eight groups of four hex digits separated by
+// dots.
+func FromDottedGroups(s string) (UUID, error) {
+	var u UUID
+	if len(s) != 39 {
+		return Nil, fmt.Errorf("uuid: incorrect dotted groups length %d in string %q", len(s), s)
+	}
+	for i := 0; i < 8; i++ {
+		if i > 0 && s[i*5-1] != '.' {
+			return Nil, fmt.Errorf("uuid: incorrect dotted groups format in string %q", s)
+		}
+		if _, err := hex.Decode(u[i*2:i*2+2], []byte(s[i*5:i*5+4])); err != nil {
+			return Nil, fmt.Errorf("uuid: invalid dotted group %q in string %q", s[i*5:i*5+4], s)
+		}
+	}
+	return u, nil
+}
+
+// ToInt64Pair returns the UUID as two signed 64-bit integers, hi holding bytes
+// 0-7 and lo holding bytes 8-15, both big-endian. This is intended for storing
+// UUIDs in databases lacking a UUID type, such as two BIGINT columns in MySQL.
+//
+// The bits of each half are reinterpreted as a two's complement int64, so
+// halves with the high bit set are negative. As a consequence the signed order
+// of the pairs does not match the order of the UUIDs as reported by Compare.
+// FromInt64Pair reverses the conversion exactly.
+func (u UUID) ToInt64Pair() (hi, lo int64) {
+	return int64(binary.BigEndian.Uint64(u[:8])), int64(binary.BigEndian.Uint64(u[8:]))
+}
+
+// FromInt64Pair returns the UUID encoded by ToInt64Pair.
+func FromInt64Pair(hi, lo int64) UUID {
+	var u UUID
+	binary.BigEndian.PutUint64(u[:8], uint64(hi))
+	binary.BigEndian.PutUint64(u[8:], uint64(lo))
+	return u
+}
+
+// decimalLen is the number of decimal digits needed for the largest 128-bit
+// value, Max.
+const decimalLen = 39
+
+// Decimal returns the UUID as its 128-bit big-endian value in decimal, zero
+// padded to a fixed width of 39 digits so that the strings sort in the same
+// order as the UUIDs. This is intended for legacy systems that store UUIDs
+// as decimal integers. FromDecimal reverses the conversion.
+func (u UUID) Decimal() string {
+	s := new(big.Int).SetBytes(u[:]).String()
+	return strings.Repeat("0", decimalLen-len(s)) + s
+}
+
+// FromDecimal parses a UUID from its 128-bit big-endian value in decimal, as
+// returned by Decimal. Leading zeros are optional, but the input must consist
+// of 1 to 39 decimal digits, with no sign, and must not exceed the value of
+// Max.
+func FromDecimal(s string) (UUID, error) {
+	if len(s) == 0 || len(s) > decimalLen {
+		return Nil, fmt.Errorf("uuid: incorrect decimal length %d in string %q", len(s), s)
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return Nil, fmt.Errorf("uuid: invalid decimal digit %q in string %q", s[i], s)
+		}
+	}
+	n, _ := new(big.Int).SetString(s, 10)
+	if n.BitLen() > 8*Size {
+		return Nil, fmt.Errorf("uuid: decimal %s overflows 128 bits", s)
+	}
+	var u UUID
+	n.FillBytes(u[:])
+	return u, nil
+}
+
+// crockfordAlphabet is the Crockford base32 alphabet used by ULIDs, which
+// excludes the letters I, L, O, and U to avoid confusion.
+const crockfordAlphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// crockfordLen is the length of a 128-bit value encoded in Crockford base32.
+const crockfordLen = 26
+
+// encodeCrockford encodes the 128-bit big-endian value b as 26 Crockford
+// base32 digits, the first of which covers only 3 bits.
+func encodeCrockford(b [16]byte) string {
+	hi := binary.BigEndian.Uint64(b[:8])
+	lo := binary.BigEndian.Uint64(b[8:])
+
+	var dst [crockfordLen]byte
+	for i := len(dst) - 1; i >= 0; i-- {
+		dst[i] = crockfordAlphabet[lo&0x1f]
+		lo = lo>>5 | hi<<59
+		hi >>= 5
+	}
+	return string(dst[:])
+}
+
+// decodeCrockford decodes 26 Crockford base32 digits, of either case, into a
+// 128-bit big-endian value.
+func decodeCrockford(s string) ([16]byte, error) {
+	var b [16]byte
+	if len(s) != crockfordLen {
+		return b, fmt.Errorf("uuid: incorrect base32 length %d in string %q", len(s), s)
+	}
+
+	var hi, lo uint64
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if 'a' <= c && c <= 'z' {
+			c -= 'a' - 'A'
+		}
+		v := strings.IndexByte(crockfordAlphabet, c)
+		if v < 0 || (i == 0 && v > 7) {
+			return b, fmt.Errorf("uuid: invalid base32 string %q", s)
+		}
+		hi = hi<<5 | lo>>59
+		lo = lo<<5 | uint64(v)
+	}
+
+	binary.BigEndian.PutUint64(b[:8], hi)
+	binary.BigEndian.PutUint64(b[8:], lo)
+	return b, nil
+}
+
+// ULID returns a millisecond precision V7 UUID as a 26 character ULID string.
+// ULIDs and V7 UUIDs share a timestamp-then-random layout, so ULIDs of V7
+// UUIDs sort chronologically like the UUIDs themselves.
+//
+// The 48-bit ULID timestamp holds the Unix time of the UUID in milliseconds.
+// The 80 random bits of the ULID hold six zero bits, followed by the 12-bit
+// seq field, and the 62 random bits of the UUID. An error is returned if u is
+// not a V7 UUID, or if its msec field is not a valid millisecond.
+func (u UUID) ULID() (string, error) {
+	if u.Version() != V7 {
+		return "", fmt.Errorf("uuid: %s is version %d, not version 7", u, u.Version())
+	}
+	d := binary.BigEndian.Uint64(u[:8])
+	sec, msec, seq := d>>28, (d>>16)&0xfff, d&0xfff
+	if msec >= 1000 {
+		return "", fmt.Errorf("uuid: %s is not a millisecond precision V7 UUID", u)
+	}
+
+	var b [16]byte
+	binary.BigEndian.PutUint64(b[:8], (sec*1000+msec)<<16|seq>>2)
+	binary.BigEndian.PutUint64(b[8:], seq<<62|binary.BigEndian.Uint64(u[8:])&(1<<62-1))
+	return encodeCrockford(b), nil
+}
+
+// FromULID parses a ULID string into a millisecond precision V7 UUID, reversing
+// ULID. Any ULID with a timestamp representable by a V7 UUID is accepted, but
+// since a V7 UUID has room for only 74 of the 80 random bits of a ULID, the
+// first six of them are dropped. Only ULIDs returned by ULID round-trip
+// exactly.
+func FromULID(s string) (UUID, error) {
+	b, err := decodeCrockford(s)
+	if err != nil {
+		return Nil, err
+	}
+	hi := binary.BigEndian.Uint64(b[:8])
+	lo := binary.BigEndian.Uint64(b[8:])
+
+	ms := hi >> 16
+	sec := ms / 1000
+	if sec >= 1<<36 {
+		return Nil, fmt.Errorf("uuid: ULID %q timestamp out of range for V7", s)
+	}
+	seq := uint16(hi&0x3ff)<<2 | uint16(lo>>62)
+
+	var u UUID
+	putV7Milli(&u, sec, ms%1000, seq)
+	binary.BigEndian.PutUint64(u[8:], lo)
+	u.SetVersion(V7)
+	u.SetVariant(VariantRFC4122)
+	return u, nil
+}
+
+// QRString returns the UUID as 26 uppercase Crockford base32 digits (0-9 and
+// A-Z, excluding I, L, O, and U). These are all part of the QR code
+// alphanumeric character set, which encodes more densely than the byte mode
+// needed for the lowercase canonical form.
+func (u UUID) QRString() string {
+	return encodeCrockford(u)
+}
+
+// FromQRString decodes a string returned by QRString back into a UUID. Lower
+// case digits are also accepted.
+func FromQRString(s string) (UUID, error) {
+	b, err := decodeCrockford(s)
+	if err != nil {
+		return Nil, err
+	}
+	return UUID(b), nil
+}
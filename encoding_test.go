@@ -0,0 +1,360 @@
+// Copyright (C) 2013-2018 by Maxim Bublis <b@codemonkey.ru>
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the
+// "Software"), to deal in the Software without restriction, including
+// without limitation the rights to use, copy, modify, merge, publish,
+// distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to
+// the following conditions:
+//
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+// MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION
+// WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package uuid
+
+import (
+	"bytes"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCursor(t *testing.T) {
+	t.Run("RoundTrip", testCursorRoundTrip)
+	t.Run("Sortable", testCursorSortable)
+	t.Run("Invalid", testCursorInvalid)
+}
+
+func testCursorRoundTrip(t *testing.T) {
+	uuids := []UUID{Nil, codecTestUUID, {0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}}
+	for i := 0; i < 100; i++ {
+		uuids = append(uuids, Must(NewV4()))
+	}
+	for _, u := range uuids {
+		c := u.Cursor()
+		got, err := CursorParse(c)
+		if err != nil {
+			t.Fatalf("CursorParse(%q): %v", c, err)
+		}
+		if got != u {
+			t.Errorf("CursorParse(%q) = %v, want %v", c, got, u)
+		}
+	}
+}
+
+func testCursorSortable(t *testing.T) {
+	uuids := make([]UUID, 1000)
+	for i := range uuids {
+		uuids[i] = Must(NewV4())
+	}
+	sort.Slice(uuids, func(i, j int) bool {
+		return bytes.Compare(uuids[i][:], uuids[j][:]) < 0
+	})
+	for i := 1; i < len(uuids); i++ {
+		p, n := uuids[i-1].Cursor(), uuids[i].Cursor()
+		if p >= n {
+			t.Errorf("cursor of %v (%q) not less than cursor of %v (%q)", uuids[i-1], p, uuids[i], n)
+		}
+	}
+}
+
+func testCursorInvalid(t *testing.T) {
+	invalid := []string{
+		"",
+		"short",
+		NamespaceDNS.String(),
+		codecTestUUID.Cursor() + "0",
+		"+" + codecTestUUID.Cursor()[1:],
+		codecTestUUID.Cursor()[:21] + "z", // non-zero trailing bits
+	}
+	for _, s := range invalid {
+		got, err := CursorParse(s)
+		if err == nil {
+			t.Errorf("CursorParse(%q): want err != nil, got %v", s, got)
+		}
+	}
+}
+
+func TestGroupedHex(t *testing.T) {
+	tests := []struct {
+		u    UUID
+		want string
+	}{
+		{u: codecTestUUID, want: "6ba7:b810:9dad:11d1:80b4:00c0:4fd4:30c8"},
+		{u: Nil, want: "0000:0000:0000:0000:0000:0000:0000:0000"},
+	}
+	for _, tt := range tests {
+		if got := tt.u.GroupedHex(); got != tt.want {
+			t.Errorf("%v.GroupedHex() = %q, want %q", tt.u, got, tt.want)
+		}
+	}
+}
+
+func TestDottedGroups(t *testing.T) {
+	const want = "6ba7.b810.9dad.11d1.80b4.00c0.4fd4.30c8"
+	if got := codecTestUUID.DottedGroups(); got != want {
+		t.Errorf("%v.DottedGroups() = %q, want %q", codecTestUUID, got, want)
+	}
+	for _, u := range []UUID{Nil, Max, codecTestUUID, Must(NewV4())} {
+		got, err := FromDottedGroups(u.DottedGroups())
+		if err != nil {
+			t.Fatalf("FromDottedGroups(%q): %v", u.DottedGroups(), err)
+		}
+		if got != u {
+			t.Errorf("FromDottedGroups(%q) = %v, want %v", u.DottedGroups(), got, u)
+		}
+	}
+	if u, err := FromDottedGroups("6BA7.B810.9DAD.11D1.80B4.00C0.4FD4.30C8"); err != nil || u != codecTestUUID {
+		t.Errorf("FromDottedGroups(upper) = %v, %v, want %v", u, err, codecTestUUID)
+	}
+
+	invalid := []string{
+		"",
+		"6ba7.b810.9dad.11d1.80b4.00c0.4fd4.30c",
+		"6ba7.b810.9dad.11d1.80b4.00c0.4fd4.30c8.",
+		"6ba7:b810:9dad:11d1:80b4:00c0:4fd4:30c8",
+		"6ba7.b810.9dad.11d1.80b4.00c0.4fd430c8.",
+		"6ba7.b810.9dad.11d1.80b4.00c0.4fd4.30cx",
+		"6ba7.b81.09dad.11d1.80b4.00c0.4fd4.30c8",
+		codecTestUUID.String(),
+	}
+	for _, s := range invalid {
+		if u, err := FromDottedGroups(s); err == nil {
+			t.Errorf("FromDottedGroups(%q) = %v, want error", s, u)
+		}
+	}
+}
+
+func TestInt64Pair(t *testing.T) {
+	tests := []struct {
+		u      UUID
+		hi, lo int64
+	}{
+		{u: Nil, hi: 0, lo: 0},
+		{u: codecTestUUID, hi: 0x6ba7b8109dad11d1, lo: -0x7f4bff3fb02bcf38},
+		{
+			u:  UUID{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x80, 0, 0, 0, 0, 0, 0, 0},
+			hi: -1,
+			lo: -1 << 63,
+		},
+		{
+			u:  UUID{0x7f, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0, 0, 0, 0, 0, 0, 0, 1},
+			hi: 1<<63 - 1,
+			lo: 1,
+		},
+	}
+	for _, tt := range tests {
+		hi, lo := tt.u.ToInt64Pair()
+		if hi != tt.hi || lo != tt.lo {
+			t.Errorf("%v.ToInt64Pair() = %d, %d, want %d, %d", tt.u, hi, lo, tt.hi, tt.lo)
+		}
+		if u := FromInt64Pair(hi, lo); u != tt.u {
+			t.Errorf("FromInt64Pair(%d, %d) = %v, want %v", hi, lo, u, tt.u)
+		}
+	}
+
+	for i := 0; i < 100; i++ {
+		u := Must(NewV4())
+		if got := FromInt64Pair(u.ToInt64Pair()); got != u {
+			t.Errorf("FromInt64Pair(%v.ToInt64Pair()) = %v", u, got)
+		}
+	}
+}
+
+func TestDecimal(t *testing.T) {
+	tests := []struct {
+		u    UUID
+		want string
+	}{
+		{u: Nil, want: "000000000000000000000000000000000000000"},
+		{u: Max, want: "340282366920938463463374607431768211455"},
+		{u: codecTestUUID, want: "143098242404177361603877621312831893704"},
+		{u: UUID{15: 1}, want: "000000000000000000000000000000000000001"},
+	}
+	for _, tt := range tests {
+		if got := tt.u.Decimal(); got != tt.want {
+			t.Errorf("%v.Decimal() = %q, want %q", tt.u, got, tt.want)
+		}
+	}
+
+	for i := 0; i < 100; i++ {
+		u := Must(NewV4())
+		if i == 0 {
+			u = Nil
+		} else if i == 1 {
+			u = Max
+		}
+		got, err := FromDecimal(u.Decimal())
+		if err != nil {
+			t.Fatalf("FromDecimal(%q): %v", u.Decimal(), err)
+		}
+		if got != u {
+			t.Errorf("FromDecimal(%q) = %v, want %v", u.Decimal(), got, u)
+		}
+	}
+
+	for _, s := range []string{"0", "1", "0001"} {
+		u, err := FromDecimal(s)
+		if err != nil {
+			t.Fatalf("FromDecimal(%q): %v", s, err)
+		}
+		if got := u.Decimal(); strings.TrimLeft(got, "0") != strings.TrimLeft(s, "0") {
+			t.Errorf("FromDecimal(%q).Decimal() = %q", s, got)
+		}
+	}
+
+	invalid := []string{
+		"",
+		"-1",
+		"+1",
+		"1_000",
+		"0x10",
+		" 1",
+		"340282366920938463463374607431768211456",  // Max + 1
+		"0340282366920938463463374607431768211455", // too long
+	}
+	for _, s := range invalid {
+		if u, err := FromDecimal(s); err == nil {
+			t.Errorf("FromDecimal(%q) = %v, want error", s, u)
+		}
+	}
+}
+
+func TestULID(t *testing.T) {
+	t.Run("Vector", testULIDVector)
+	t.Run("RoundTrip", testULIDRoundTrip)
+	t.Run("Sortable", testULIDSortable)
+	t.Run("Invalid", testULIDInvalid)
+}
+
+func testULIDVector(t *testing.T) {
+	// 2021-11-26T12:34:56.123Z, seq 5
+	u := Must(FromString("061a0d47-007b-7005-8123-456789abcdef"))
+	want := "01FNE3VNFV000M28T5CY4TQKFF"
+
+	got, err := u.ULID()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != want {
+		t.Errorf("%v.ULID() = %q, want %q", u, got, want)
+	}
+	for _, s := range []string{want, strings.ToLower(want)} {
+		if got, err := FromULID(s); err != nil || got != u {
+			t.Errorf("FromULID(%q) = %v, %v, want %v", s, got, err, u)
+		}
+	}
+}
+
+func testULIDRoundTrip(t *testing.T) {
+	for i := 0; i < 1000; i++ {
+		u := Must(NewV7(MillisecondPrecision))
+		s, err := u.ULID()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(s) != 26 {
+			t.Fatalf("%v.ULID() = %q, want 26 characters", u, s)
+		}
+		got, err := FromULID(s)
+		if err != nil {
+			t.Fatalf("FromULID(%q): %v", s, err)
+		}
+		if got != u {
+			t.Fatalf("FromULID(%v.ULID()) = %v", u, got)
+		}
+	}
+}
+
+func testULIDSortable(t *testing.T) {
+	now := time.Date(2021, 11, 26, 12, 34, 56, 0, time.UTC)
+	g := NewGen()
+	g.epochFunc = func() time.Time { return now }
+
+	var ulids []string
+	for i := 0; i < 100; i++ {
+		if i%10 == 0 {
+			now = now.Add(997 * time.Millisecond)
+		}
+		s, err := Must(g.NewV7(MillisecondPrecision)).ULID()
+		if err != nil {
+			t.Fatal(err)
+		}
+		ulids = append(ulids, s)
+	}
+	if !sort.StringsAreSorted(ulids) {
+		t.Errorf("ULIDs of consecutive V7 UUIDs are not sorted: %q", ulids)
+	}
+}
+
+func testULIDInvalid(t *testing.T) {
+	for _, u := range []UUID{Nil, Must(NewV4()), Must(FromString("061a0d47-0fff-7005-8123-456789abcdef"))} {
+		if s, err := u.ULID(); err == nil {
+			t.Errorf("%v.ULID() = %q, want error", u, s)
+		}
+	}
+
+	inputs := []string{
+		"",
+		"01FNE3VNFV000M28T5CY4TQKF",
+		"01FNE3VNFV000M28T5CY4TQKFFF",
+		"81FNE3VNFV000M28T5CY4TQKFF", // overflows 128 bits
+		"01FNE3VNFV000M28T5CY4TQKFU",
+		"01FNE3VNFV000M28T5CY4TQKF-",
+		"7ZZZZZZZZZ0000000000000000", // timestamp out of range for V7
+	}
+	for _, s := range inputs {
+		if u, err := FromULID(s); err == nil {
+			t.Errorf("FromULID(%q) = %v, want error", s, u)
+		}
+	}
+}
+
+func TestQRString(t *testing.T) {
+	const qrAlphanumeric = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZ $%*+-./:"
+
+	uuids := []UUID{Nil, Max, codecTestUUID}
+	for i := 0; i < 100; i++ {
+		uuids = append(uuids, Must(NewV4()))
+	}
+	for _, u := range uuids {
+		s := u.QRString()
+		if len(s) != 26 {
+			t.Fatalf("%v.QRString() = %q, want 26 characters", u, s)
+		}
+		for _, r := range s {
+			if !strings.ContainsRune(qrAlphanumeric, r) {
+				t.Fatalf("%v.QRString() = %q, contains non-alphanumeric %q", u, s, r)
+			}
+		}
+		for _, in := range []string{s, strings.ToLower(s)} {
+			got, err := FromQRString(in)
+			if err != nil {
+				t.Fatalf("FromQRString(%q): %v", in, err)
+			}
+			if got != u {
+				t.Errorf("FromQRString(%q) = %v, want %v", in, got, u)
+			}
+		}
+	}
+
+	if got, want := Max.QRString(), "7ZZZZZZZZZZZZZZZZZZZZZZZZZ"; got != want {
+		t.Errorf("%v.QRString() = %q, want %q", Max, got, want)
+	}
+
+	for _, s := range []string{"", "0000000000000000000000000", "8ZZZZZZZZZZZZZZZZZZZZZZZZZ", "0000000000000000000000000O"} {
+		if u, err := FromQRString(s); err == nil {
+			t.Errorf("FromQRString(%q) = %v, want error", s, u)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+// Copyright (C) 2013-2018 by Maxim Bublis <b@codemonkey.ru>
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the
+// "Software"), to deal in the Software without restriction, including
+// without limitation the rights to use, copy, modify, merge, publish,
+// distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to
+// the following conditions:
+//
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+// MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION
+// WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package uuid
+
+import (
+	"crypto/rand"
+	"io"
+	"sync"
+	"sync/atomic"
+)
+
+// entropyBufSize is the number of random bytes read from crypto/rand at a
+// time by readEntropy, enough for 64 V4 UUIDs.
+const entropyBufSize = 64 * Size
+
+// entropyBuf is a buffer of random bytes, of which b[off:] are unused.
+type entropyBuf struct {
+	b   [entropyBufSize]byte
+	off int
+}
+
+// entropyPool caches entropy buffers. Since a sync.Pool keeps a per-P cache
+// of its items, concurrent callers will almost always get their own buffer
+// without contending on a lock, and only pay for a read from crypto/rand once
+// every 64 UUIDs.
+var entropyPool = sync.Pool{
+	New: func() interface{} {
+		return &entropyBuf{off: entropyBufSize}
+	},
+}
+
+// readEntropy fills dst, which must be no larger than entropyBufSize, with
+// random bytes from crypto/rand by way of entropyPool. The bytes handed out
+// are zeroed in the buffer so they don't linger in memory after use.
+func readEntropy(dst []byte) error {
+	e := entropyPool.Get().(*entropyBuf)
+	defer entropyPool.Put(e)
+
+	if len(dst) > len(e.b)-e.off {
+		if _, err := io.ReadFull(rand.Reader, e.b[:]); err != nil {
+			e.off = len(e.b)
+			return err
+		}
+		e.off = 0
+	}
+
+	src := e.b[e.off : e.off+len(dst)]
+	copy(dst, src)
+	for i := range src {
+		src[i] = 0
+	}
+	e.off += len(dst)
+
+	return nil
+}
+
+// randPoolOn is non-zero if V4 UUIDs should be read from readEntropy, see
+// EnableRandPool.
+var randPoolOn int32
+
+// EnableRandPool makes V4 UUIDs generated with crypto/rand be served from a
+// pool of random bytes. It is off by default. The pool reads crypto/rand 1 KiB
+// at a time and keeps the unused bytes in a per-P cache, so that most calls to
+// NewV4 make no system call and don't contend with each other. How much this
+// saves depends on the cost of reading crypto/rand on the platform; see
+// BenchmarkNewV4Parallel.
+//
+// The tradeoff is that random bytes for up to 63 future UUIDs per P are held
+// in memory before they are used, where they could be exposed by a memory
+// disclosure. Bytes are zeroed in the pool as they are handed out. Generators
+// with a random reader other than crypto/rand.Reader never use the pool.
+//
+// EnableRandPool affects every generator in the process and is meant to be
+// called once during program initialization.
+func EnableRandPool() {
+	atomic.StoreInt32(&randPoolOn, 1)
+}
+
+// DisableRandPool disables the pool of random bytes enabled by
+// EnableRandPool, so that every V4 UUID is read from crypto/rand directly.
+// Bytes already in the pool are not discarded, but are no longer used.
+func DisableRandPool() {
+	atomic.StoreInt32(&randPoolOn, 0)
+}
+
+// randPoolEnabled reports whether V4 UUIDs should be read from the pool.
+func randPoolEnabled() bool {
+	return atomic.LoadInt32(&randPoolOn) != 0
+}
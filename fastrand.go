@@ -0,0 +1,66 @@
+// Copyright (C) 2013-2018 by Maxim Bublis <b@codemonkey.ru>
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the
+// "Software"), to deal in the Software without restriction, including
+// without limitation the rights to use, copy, modify, merge, publish,
+// distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to
+// the following conditions:
+//
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+// MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION
+// WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+//go:build go1.22
+// +build go1.22
+
+package uuid
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	mathrand "math/rand/v2"
+	"sync"
+)
+
+// fastRandPool caches ChaCha8 generators for NewV4Fast, each seeded from
+// crypto/rand, since a ChaCha8 is not safe for concurrent use.
+var fastRandPool = sync.Pool{
+	New: func() interface{} {
+		var seed [32]byte
+		if _, err := rand.Read(seed[:]); err != nil {
+			panic("uuid: failed to seed ChaCha8: " + err.Error())
+		}
+		return mathrand.NewChaCha8(seed)
+	},
+}
+
+// NewV4Fast returns a V4 UUID whose random bits come from a ChaCha8 generator
+// of math/rand/v2 instead of crypto/rand, for workloads such as test data and
+// tracing IDs where throughput matters more than cryptographic guarantees.
+// The generators are seeded from crypto/rand and cached per-P, so NewV4Fast
+// never makes a system call after warming up and scales with GOMAXPROCS.
+//
+// Although ChaCha8 is a strong generator, its state is kept in ordinary
+// memory and is not erased after use, so UUIDs returned by NewV4Fast must not
+// be used as secrets or security tokens; use NewV4 for those.
+func NewV4Fast() UUID {
+	r := fastRandPool.Get().(*mathrand.ChaCha8)
+	var u UUID
+	binary.LittleEndian.PutUint64(u[:8], r.Uint64())
+	binary.LittleEndian.PutUint64(u[8:], r.Uint64())
+	fastRandPool.Put(r)
+
+	u.SetVersion(V4)
+	u.SetVariant(VariantRFC4122)
+
+	return u
+}
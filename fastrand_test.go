@@ -0,0 +1,71 @@
+// Copyright (C) 2013-2018 by Maxim Bublis <b@codemonkey.ru>
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the
+// "Software"), to deal in the Software without restriction, including
+// without limitation the rights to use, copy, modify, merge, publish,
+// distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to
+// the following conditions:
+//
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+// MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION
+// WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+//go:build go1.22
+// +build go1.22
+
+package uuid
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestNewV4Fast(t *testing.T) {
+	const goroutines = 4
+	const n = 1000
+
+	results := make([][]UUID, goroutines)
+	var wg sync.WaitGroup
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			us := make([]UUID, n)
+			for j := range us {
+				us[j] = NewV4Fast()
+			}
+			results[i] = us
+		}(i)
+	}
+	wg.Wait()
+
+	seen := make(map[UUID]bool, goroutines*n)
+	for _, us := range results {
+		for _, u := range us {
+			if u.Version() != V4 || u.Variant() != VariantRFC4122 {
+				t.Fatalf("%v has version %d and variant %d", u, u.Version(), u.Variant())
+			}
+			if seen[u] {
+				t.Fatalf("duplicate UUID: %v", u)
+			}
+			seen[u] = true
+		}
+	}
+}
+
+func BenchmarkNewV4Fast(b *testing.B) {
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			NewV4Fast()
+		}
+	})
+}
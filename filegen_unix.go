@@ -0,0 +1,102 @@
+// Copyright (C) 2013-2018 by Maxim Bublis <b@codemonkey.ru>
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the
+// "Software"), to deal in the Software without restriction, including
+// without limitation the rights to use, copy, modify, merge, publish,
+// distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to
+// the following conditions:
+//
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+// MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION
+// WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd
+// +build darwin dragonfly freebsd linux netbsd openbsd
+
+package uuid
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"syscall"
+)
+
+// fileStateSize is the size of the state persisted by FileMonotonicGen: the
+// last Unix timestamp in milliseconds followed by the sequence, both
+// big-endian.
+const fileStateSize = 8 + 2
+
+// NewV7FileMonotonic returns a FileMonotonicGen persisting its state to the
+// file at path, which is created if it doesn't exist, and configured with
+// opts, which have the same meaning as for NewGenWithOptions. The generator
+// must be closed with Close when no longer needed.
+func NewV7FileMonotonic(path string, opts ...GenOption) (*FileMonotonicGen, error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, err
+	}
+	return &FileMonotonicGen{gen: NewGenWithOptions(opts...), f: f}, nil
+}
+
+// next locks the state file, advances the stored timestamp and sequence, and
+// returns them.
+func (g *FileMonotonicGen) next() (ms uint64, seq uint16, err error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	fd := int(g.f.Fd())
+	if err := syscall.Flock(fd, syscall.LOCK_EX); err != nil {
+		return 0, 0, fmt.Errorf("uuid: locking %s: %v", g.f.Name(), err)
+	}
+	defer syscall.Flock(fd, syscall.LOCK_UN)
+
+	var buf [fileStateSize]byte
+	n, err := g.f.ReadAt(buf[:], 0)
+	switch {
+	case err == io.EOF && n == 0:
+		// new state file
+	case err != nil && err != io.EOF:
+		return 0, 0, err
+	case n != fileStateSize:
+		return 0, 0, fmt.Errorf("uuid: corrupt state file %s: %d bytes", g.f.Name(), n)
+	}
+	lastMs := binary.BigEndian.Uint64(buf[:8])
+	lastSeq := binary.BigEndian.Uint16(buf[8:])
+
+	t := g.gen.epochFunc()
+	if t.Unix() < 0 || t.Unix() >= 1<<36 {
+		return 0, 0, fmt.Errorf("uuid: time %v out of range for V7", t)
+	}
+	ms = uint64(t.Unix())*1000 + uint64(t.Nanosecond()/1000000)
+
+	switch {
+	case n == 0 || ms > lastMs:
+		seq = 0
+	case lastSeq < maxSeq12:
+		ms, seq = lastMs, lastSeq+1
+	default:
+		ms, seq = lastMs+1, 0
+	}
+
+	binary.BigEndian.PutUint64(buf[:8], ms)
+	binary.BigEndian.PutUint16(buf[8:], seq)
+	if _, err := g.f.WriteAt(buf[:], 0); err != nil {
+		return 0, 0, err
+	}
+	if err := g.f.Sync(); err != nil {
+		return 0, 0, err
+	}
+
+	return ms, seq, nil
+}
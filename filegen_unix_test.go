@@ -0,0 +1,224 @@
+// Copyright (C) 2013-2018 by Maxim Bublis <b@codemonkey.ru>
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the
+// "Software"), to deal in the Software without restriction, including
+// without limitation the rights to use, copy, modify, merge, publish,
+// distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to
+// the following conditions:
+//
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+// MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION
+// WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd
+// +build darwin dragonfly freebsd linux netbsd openbsd
+
+package uuid
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func testFileMonotonicPath(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "uuid")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	return filepath.Join(dir, "v7.state")
+}
+
+func openFileMonotonic(t *testing.T, path string, now func() time.Time) *FileMonotonicGen {
+	t.Helper()
+	g, err := NewV7FileMonotonic(path, WithEpochFunc(now))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return g
+}
+
+func TestFileMonotonicGen(t *testing.T) {
+	t.Run("Restart", testFileMonotonicGenRestart)
+	t.Run("ClockBackwards", testFileMonotonicGenClockBackwards)
+	t.Run("SequenceRollover", testFileMonotonicGenSequenceRollover)
+	t.Run("Concurrent", testFileMonotonicGenConcurrent)
+	t.Run("Corrupt", testFileMonotonicGenCorrupt)
+	t.Run("Generator", testFileMonotonicGenGenerator)
+}
+
+func testFileMonotonicGenRestart(t *testing.T) {
+	path := testFileMonotonicPath(t)
+	now := time.Date(2021, 11, 26, 12, 34, 56, 0, time.UTC)
+	frozen := func() time.Time { return now }
+
+	var prev UUID
+	for restart := 0; restart < 3; restart++ {
+		g := openFileMonotonic(t, path, frozen)
+		for i := 0; i < 10; i++ {
+			u, err := g.NewV7(MillisecondPrecision)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if u.Version() != V7 || u.Variant() != VariantRFC4122 {
+				t.Fatalf("%v has version %d and variant %d", u, u.Version(), u.Variant())
+			}
+			if Compare(prev, u) >= 0 {
+				t.Fatalf("restart %d: %v is not greater than %v", restart, u, prev)
+			}
+			prev = u
+		}
+		if err := g.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if got, _ := TimeFromV7(prev, MillisecondPrecision); !got.Equal(now) {
+		t.Errorf("TimeFromV7(%v) = %v, want %v", prev, got, now)
+	}
+}
+
+func testFileMonotonicGenClockBackwards(t *testing.T) {
+	path := testFileMonotonicPath(t)
+	now := time.Date(2021, 11, 26, 12, 34, 56, 0, time.UTC)
+
+	g := openFileMonotonic(t, path, func() time.Time { return now })
+	u1, err := g.NewV7(MillisecondPrecision)
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.Close()
+
+	g = openFileMonotonic(t, path, func() time.Time { return now.Add(-time.Hour) })
+	defer g.Close()
+	u2, err := g.NewV7(MillisecondPrecision)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if Compare(u1, u2) >= 0 {
+		t.Errorf("%v generated after a clock regression is not greater than %v", u2, u1)
+	}
+}
+
+func testFileMonotonicGenSequenceRollover(t *testing.T) {
+	path := testFileMonotonicPath(t)
+	now := time.Date(2021, 11, 26, 12, 34, 56, 0, time.UTC)
+	g := openFileMonotonic(t, path, func() time.Time { return now })
+	defer g.Close()
+
+	var prev UUID
+	for i := 0; i <= maxSeq12+1; i++ {
+		u, err := g.NewV7(MillisecondPrecision)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if Compare(prev, u) >= 0 {
+			t.Fatalf("%v is not greater than %v", u, prev)
+		}
+		prev = u
+	}
+	want := now.Add(time.Millisecond)
+	if got, _ := TimeFromV7(prev, MillisecondPrecision); !got.Equal(want) {
+		t.Errorf("TimeFromV7(%v) = %v, want %v", prev, got, want)
+	}
+}
+
+func testFileMonotonicGenConcurrent(t *testing.T) {
+	path := testFileMonotonicPath(t)
+	now := time.Date(2021, 11, 26, 12, 34, 56, 0, time.UTC)
+	frozen := func() time.Time { return now }
+
+	// separate generators hold separate open files, and so contend on the
+	// flock like generators in different processes would
+	const generators, n = 4, 50
+	results := make([][]UUID, generators)
+	var wg sync.WaitGroup
+	for i := range results {
+		g := openFileMonotonic(t, path, frozen)
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			defer g.Close()
+			for j := 0; j < n; j++ {
+				u, err := g.NewV7(MillisecondPrecision)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				results[i] = append(results[i], u)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	seen := make(map[int64]bool)
+	for _, us := range results {
+		for i, u := range us {
+			if i > 0 && Compare(us[i-1], u) >= 0 {
+				t.Fatalf("%v is not greater than %v", u, us[i-1])
+			}
+			key, _ := u.ToInt64Pair()
+			if seen[key] {
+				t.Fatalf("timestamp and sequence of %v were handed out twice", u)
+			}
+			seen[key] = true
+		}
+	}
+}
+
+func testFileMonotonicGenCorrupt(t *testing.T) {
+	path := testFileMonotonicPath(t)
+	if err := ioutil.WriteFile(path, []byte{1, 2, 3}, 0644); err != nil {
+		t.Fatal(err)
+	}
+	g := openFileMonotonic(t, path, time.Now)
+	defer g.Close()
+	if u, err := g.NewV7(MillisecondPrecision); err == nil {
+		t.Errorf("NewV7() with a corrupt state file = %v, want error", u)
+	}
+}
+
+func testFileMonotonicGenGenerator(t *testing.T) {
+	path := testFileMonotonicPath(t)
+	now := time.Date(2021, 11, 26, 12, 34, 56, 0, time.UTC)
+	g := openFileMonotonic(t, path, func() time.Time { return now })
+	defer g.Close()
+
+	defer SetDefaultGenerator(SetDefaultGenerator(g))
+	u1 := Must(NewV7(MillisecondPrecision))
+	g2 := openFileMonotonic(t, path, func() time.Time { return now })
+	defer g2.Close()
+	u2 := Must(g2.NewV7(MillisecondPrecision))
+	if Compare(u1, u2) >= 0 {
+		t.Errorf("%v from a second generator is not greater than %v from the default", u2, u1)
+	}
+
+	// the other versions don't use the state file
+	for _, u := range []UUID{Must(NewV1()), Must(NewV4()), Must(NewV6()), NewV3(NamespaceDNS, "a"), NewV5(NamespaceDNS, "a")} {
+		if u.Variant() != VariantRFC4122 {
+			t.Errorf("%v has variant %d", u, u.Variant())
+		}
+	}
+	if u, err := g2.NewV7(MillisecondPrecision); err != nil || Compare(u2, u) >= 0 {
+		t.Errorf("NewV7() = %v, %v, want a UUID after %v", u, err, u2)
+	}
+
+	for _, p := range []Precision{MicrosecondPrecision, NanosecondPrecision} {
+		if u, err := g.NewV7(p); err == nil {
+			t.Errorf("NewV7(%s) = %v, want error", p, u)
+		}
+	}
+}
@@ -22,6 +22,8 @@
 package uuid
 
 import (
+	"bufio"
+	"crypto"
 	"crypto/md5"
 	"crypto/rand"
 	"crypto/sha1"
@@ -31,22 +33,104 @@ import (
 	"hash"
 	"io"
 	"net"
+	"net/url"
+	"strings"
 	"sync"
 	"time"
 )
 
-// Difference in 100-nanosecond intervals between
-// UUID epoch (October 15, 1582) and Unix epoch (January 1, 1970).
-const epochStart = 122192928000000000
+// GregorianEpochOffset is the difference in 100-nanosecond intervals between
+// the UUID epoch (October 15, 1582) and the Unix epoch (January 1, 1970). It
+// can be used for custom arithmetic on V1 and V6 timestamps, which count
+// 100-nanosecond intervals since the UUID epoch.
+const GregorianEpochOffset = 122192928000000000
 
-type epochFunc func() time.Time
+// EpochFunc is the function type used to get the current time.
+type EpochFunc func() time.Time
 
 // HWAddrFunc is the function type used to provide hardware (MAC) addresses.
 type HWAddrFunc func() (net.HardwareAddr, error)
 
-// DefaultGenerator is the default UUID Generator used by this package.
+// ClockState is the state of the V1 and V6 generator that RFC-4122 section
+// 4.2.1 recommends keeping in stable storage.
+type ClockState struct {
+	// LastTime is the timestamp of the last UUID generated, in 100-nanosecond
+	// intervals since the UUID epoch.
+	LastTime uint64
+
+	// ClockSequence is the clock sequence of the last UUID generated.
+	ClockSequence uint16
+}
+
+// StableStorageFunc is the function type used to persist the V1 and V6 clock
+// state, see WithStableStorage. It must load the stored state, or the zero
+// ClockState if there is none, pass it to update, and store the state that
+// update returns. Implementations shared between processes should hold a lock
+// on the storage for the duration of the call.
+type StableStorageFunc func(update func(ClockState) ClockState) error
+
+// DefaultGenerator is the default UUID Generator used by this package. All of
+// the package-level NewVx functions use it.
+//
+// It may be assigned directly, for example at program start, but assignments
+// are not synchronized with UUID generation. SetDefaultGenerator and
+// CurrentGenerator synchronize with each other.
 var DefaultGenerator Generator = NewGen()
 
+// defaultGeneratorMutex is held by SetDefaultGenerator and CurrentGenerator
+// while they access DefaultGenerator.
+var defaultGeneratorMutex sync.RWMutex
+
+// CurrentGenerator returns DefaultGenerator, the Generator used by the
+// package-level functions.
+func CurrentGenerator() Generator {
+	defaultGeneratorMutex.RLock()
+	defer defaultGeneratorMutex.RUnlock()
+	return DefaultGenerator
+}
+
+// SetDefaultGenerator replaces DefaultGenerator with g and returns the
+// previous value, so that it can be restored afterwards:
+//
+//	defer uuid.SetDefaultGenerator(uuid.SetDefaultGenerator(g))
+//
+// All of the package-level NewVx functions use DefaultGenerator, so this
+// affects every caller in the process. Like assigning DefaultGenerator, it
+// is not synchronized with UUID generation and is intended to be used
+// carefully, typically only in tests that need a deterministic generator.
+// It panics if g is nil.
+//
+// Package-level functions that are not part of the Generator interface, such
+// as NewV7Batch, call the method of the same name on the default and return
+// an error if it does not have one. NewV4Batch and FillV4 fall back to calling
+// NewV4 for each UUID.
+func SetDefaultGenerator(g Generator) Generator {
+	if g == nil {
+		panic("uuid: SetDefaultGenerator called with a nil Generator")
+	}
+
+	defaultGeneratorMutex.Lock()
+	defer defaultGeneratorMutex.Unlock()
+
+	prev := DefaultGenerator
+	DefaultGenerator = g
+	return prev
+}
+
+// DefaultRandomNodePreferred, if set, makes every Gen behave as if its
+// RandomNodePreferred field were set, including the initial DefaultGenerator,
+// so that V1 UUIDs generated anywhere in the process use a random multicast
+// node instead of the hardware address. A generator reads it once, when it
+// chooses its node for the first V1 UUID, so it should be set at program
+// start. It has no effect on generators built with WithNodeID.
+var DefaultRandomNodePreferred bool
+
+// unsupportedError returns the error returned by a package-level function when
+// the default Generator g does not implement method.
+func unsupportedError(g Generator, method string) error {
+	return fmt.Errorf("uuid: default generator %T does not implement %s", g, method)
+}
+
 // NewV1 returns a UUID based on the current timestamp and MAC address.
 func NewV1() (UUID, error) {
 	return DefaultGenerator.NewV1()
@@ -57,16 +141,341 @@ func NewV3(ns UUID, name string) UUID {
 	return DefaultGenerator.NewV3(ns, name)
 }
 
+// NewV3Bytes is like NewV3, but hashes a []byte name directly, avoiding a
+// conversion to string for names that are already binary. It does not
+// allocate for names of up to 240 bytes.
+func NewV3Bytes(ns UUID, name []byte) UUID {
+	var buf [nameBufSize]byte
+	u := UUID(md5.Sum(appendNamespaceName(&buf, ns, name)))
+	u.SetVersion(V3)
+	u.SetVariant(VariantRFC4122)
+
+	return u
+}
+
 // NewV4 returns a randomly generated UUID.
 func NewV4() (UUID, error) {
 	return DefaultGenerator.NewV4()
 }
 
+// NewV4FromReader returns a V4 UUID whose random bits are read from r instead
+// of crypto/rand, for environments such as FIPS deployments and fuzz harnesses
+// that need to control the source of randomness. To use a custom source for
+// all of the package-level functions, replace the default generator instead:
+//
+//	uuid.SetDefaultGenerator(uuid.NewGenWithOptions(uuid.WithRandomReader(r)))
+func NewV4FromReader(r io.Reader) (UUID, error) {
+	u := UUID{}
+	if _, err := io.ReadFull(r, u[:]); err != nil {
+		return Nil, err
+	}
+	u.SetVersion(V4)
+	u.SetVariant(VariantRFC4122)
+
+	return u, nil
+}
+
+// GenerateV4N generates n V4 UUIDs with the default Generator and writes them to
+// w in canonical form, each followed by a newline. Writes are buffered, and
+// the first error from either generating or writing stops the output and is
+// returned.
+func GenerateV4N(w io.Writer, n int) error {
+	if n < 0 {
+		return fmt.Errorf("uuid: invalid UUID count %d", n)
+	}
+
+	g := DefaultGenerator
+	bw := bufio.NewWriter(w)
+	var buf [37]byte
+	buf[36] = '\n'
+	for i := 0; i < n; i++ {
+		u, err := g.NewV4()
+		if err != nil {
+			return err
+		}
+		encodeCanonical(buf[:], u)
+		if _, err := bw.Write(buf[:]); err != nil {
+			return err
+		}
+	}
+
+	return bw.Flush()
+}
+
+// NewV4Batch returns n randomly generated UUIDs, reading the random bytes for
+// all of them at once. For bulk workloads this is much faster than calling
+// NewV4 n times.
+//
+// If the default Generator has no NewV4Batch method, its NewV4 method is
+// called for each UUID instead.
+func NewV4Batch(n int) ([]UUID, error) {
+	g := DefaultGenerator
+	if b, ok := g.(interface {
+		NewV4Batch(int) ([]UUID, error)
+	}); ok {
+		return b.NewV4Batch(n)
+	}
+	if n < 0 {
+		return nil, fmt.Errorf("uuid: invalid UUID count %d", n)
+	}
+	us := make([]UUID, n)
+	if err := fillV4(g, us); err != nil {
+		return nil, err
+	}
+	return us, nil
+}
+
+// FillV4 fills dst with randomly generated UUIDs, like NewV4Batch but without
+// allocating the result.
+//
+// If the default Generator has no FillV4 method, its NewV4 method is called
+// for each UUID instead.
+func FillV4(dst []UUID) error {
+	g := DefaultGenerator
+	if f, ok := g.(interface{ FillV4([]UUID) error }); ok {
+		return f.FillV4(dst)
+	}
+	return fillV4(g, dst)
+}
+
+// fillV4 fills dst by calling g.NewV4 for each UUID.
+func fillV4(g Generator, dst []UUID) error {
+	for i := range dst {
+		u, err := g.NewV4()
+		if err != nil {
+			return err
+		}
+		dst[i] = u
+	}
+	return nil
+}
+
 // NewV5 returns a UUID based on SHA-1 hash of the namespace UUID and name.
 func NewV5(ns UUID, name string) UUID {
 	return DefaultGenerator.NewV5(ns, name)
 }
 
+// NewV5Bytes is like NewV5, but hashes a []byte name directly, avoiding a
+// conversion to string for names that are already binary. It does not
+// allocate for names of up to 240 bytes.
+func NewV5Bytes(ns UUID, name []byte) UUID {
+	var buf [nameBufSize]byte
+	sum := sha1.Sum(appendNamespaceName(&buf, ns, name))
+	u := UUID{}
+	copy(u[:], sum[:])
+	u.SetVersion(V5)
+	u.SetVariant(VariantRFC4122)
+
+	return u
+}
+
+// V5Builder builds V5 UUIDs from a stored namespace and name, which makes it
+// convenient to hash the same name under several namespaces:
+//
+//	b := uuid.NewV5Builder(ns, "foo")
+//	u1 := b.UUID()
+//	u2 := b.WithNamespace(ns2).UUID()
+//
+// V5Builder values are immutable and safe for concurrent use.
+type V5Builder struct {
+	ns   UUID
+	name string
+}
+
+// NewV5Builder returns a V5Builder for name under the namespace ns.
+func NewV5Builder(ns UUID, name string) V5Builder {
+	return V5Builder{ns: ns, name: name}
+}
+
+// WithNamespace returns a copy of b that uses the namespace ns.
+func (b V5Builder) WithNamespace(ns UUID) V5Builder {
+	b.ns = ns
+	return b
+}
+
+// WithName returns a copy of b that uses name.
+func (b V5Builder) WithName(name string) V5Builder {
+	b.name = name
+	return b
+}
+
+// UUID returns the V5 UUID of the builder's namespace and name, which is the
+// same as calling NewV5 with them.
+func (b V5Builder) UUID() UUID {
+	return NewV5(b.ns, b.name)
+}
+
+// NewNamespace returns a namespace UUID for name, suitable for use with NewV3
+// and NewV5, so that applications can define their own namespaces without
+// hardcoding a UUID literal:
+//
+//	var usersNamespace = uuid.NewNamespace("com.example.users")
+//
+// The namespace is the V5 UUID of name under NamespaceOID, so the same name
+// always yields the same namespace.
+func NewNamespace(name string) UUID {
+	return NewV5(NamespaceOID, name)
+}
+
+// TenantID returns a deterministic UUID for key scoped to tenant, for use in
+// multi-tenant systems. It is the V5 UUID of key using the tenant UUID as the
+// namespace, so the same (tenant, key) pair always maps to the same UUID while
+// the same key maps to unrelated UUIDs for different tenants.
+func TenantID(tenant UUID, key string) UUID {
+	return NewV5(tenant, key)
+}
+
+// VerifyV5 reports whether u is the V5 UUID of name within namespace ns.
+// The name cannot be recovered from a V5 UUID, but VerifyV5 can be used to
+// prove that a candidate (namespace, name) pair produced it.
+func VerifyV5(u, ns UUID, name string) bool {
+	return NewV5(ns, name) == u
+}
+
+// NewV8 returns a V8 UUID holding the application-defined data in custom, as
+// described by RFC 9562 section 5.8. Only the version and variant bits are
+// overwritten, leaving the remaining 122 bits as supplied by the caller, which
+// is responsible for their uniqueness. This allows vendor-specific layouts,
+// such as k-sortable IDs with a custom timestamp, to be built on top of this
+// package.
+func NewV8(custom [16]byte) UUID {
+	u := UUID(custom)
+	u.SetVersion(V8)
+	u.SetVariant(VariantRFC4122)
+
+	return u
+}
+
+// getV8Epoch returns the epoch of V8 UUIDs from NewV8Epoch.
+func (g *Gen) getV8Epoch() time.Time {
+	if g.v8Epoch.IsZero() {
+		return time.Unix(0, 0)
+	}
+	return g.v8Epoch
+}
+
+// NewV8Epoch returns a k-sortable V8 UUID laid out like an RFC 9562 V7 UUID,
+// with a 48-bit count of milliseconds followed by random data, but counting
+// from the epoch set with WithV8Epoch rather than the Unix epoch. Since the
+// epoch is not encoded within the UUID, use Gen.TimeFromV8 or TimeFromV8Epoch
+// to decode the timestamp. An error is returned if the clock is before the
+// epoch or too far after it to be represented.
+func (g *Gen) NewV8Epoch() (UUID, error) {
+	var u UUID
+	if _, err := io.ReadFull(g.rand, u[6:]); err != nil {
+		return Nil, err
+	}
+
+	epoch := g.getV8Epoch()
+	tn := g.epochFunc()
+	// the range exceeds that of time.Duration, so the difference is
+	// computed in seconds and nanoseconds
+	nsec := int64(tn.Nanosecond()) - int64(epoch.Nanosecond())
+	ms := (tn.Unix()-epoch.Unix())*1000 + nsec/1000000
+	if nsec%1000000 < 0 {
+		ms--
+	}
+	if tn.Before(epoch) || ms >= 1<<48 {
+		return Nil, fmt.Errorf("uuid: time %v out of range for V8 with epoch %v", tn, epoch)
+	}
+	var b [8]byte
+	binary.BigEndian.PutUint64(b[:], uint64(ms))
+	copy(u[:6], b[2:])
+
+	u.SetVersion(V8)
+	u.SetVariant(VariantRFC4122)
+
+	return u, nil
+}
+
+// TimeFromV8 returns the time embedded within a V8 UUID generated by
+// NewV8Epoch, using the generator's epoch.
+func (g *Gen) TimeFromV8(u UUID) (time.Time, error) {
+	return TimeFromV8Epoch(u, g.getV8Epoch())
+}
+
+// NewV8Name returns a name-based V8 UUID, generalizing V3 and V5 to hash
+// functions other than MD5 and SHA-1. The namespace UUID and name are hashed
+// with a new hash.Hash returned by h, the digest is truncated to 16 bytes, and
+// the version and variant bits are set as for any V8 UUID:
+//
+//	u := uuid.NewV8Name(uuid.NamespaceDNS, []byte("www.example.com"), sha256.New)
+//
+// NewV8Name panics if the digest produced by h is shorter than 16 bytes.
+func NewV8Name(ns UUID, name []byte, h func() hash.Hash) UUID {
+	hh := h()
+	hh.Write(ns[:])
+	hh.Write(name)
+	sum := hh.Sum(nil)
+	if len(sum) < Size {
+		panic(fmt.Sprintf("uuid: hash digest size %d is smaller than %d", len(sum), Size))
+	}
+
+	u := UUID{}
+	copy(u[:], sum)
+	u.SetVersion(V8)
+	u.SetVariant(VariantRFC4122)
+
+	return u
+}
+
+// NewV8Hash is like NewV8Name, but takes the hash function as a crypto.Hash,
+// which makes it easy to select it from configuration. RFC 9562 appendix B.2
+// gives an example using SHA-256, a drop-in replacement for the deprecated
+// MD5 and SHA-1 hashes of V3 and V5:
+//
+//	u := uuid.NewV8Hash(uuid.NamespaceDNS, []byte("www.example.com"), crypto.SHA256)
+//
+// The package implementing h must be linked into the binary, for example by
+// importing crypto/sha256. NewV8Hash panics if h is not available or its
+// digest is shorter than 16 bytes.
+func NewV8Hash(ns UUID, name []byte, h crypto.Hash) UUID {
+	if !h.Available() {
+		panic(fmt.Sprintf("uuid: hash function %v is not available", h))
+	}
+	return NewV8Name(ns, name, h.New)
+}
+
+// NewV5FromURL returns a V5 UUID for rawurl under NamespaceURL, after
+// normalizing the URL so that equivalent URLs map to the same UUID. The
+// following normalization rules are applied:
+//
+//   - the scheme and host are lowercased
+//   - the port is removed if it is the default for the scheme (80 for http,
+//     443 for https)
+//   - query parameters are sorted by key, the relative order of values for
+//     the same key is preserved
+//
+// The path and fragment are left as is. An error is returned if rawurl cannot
+// be parsed.
+func NewV5FromURL(rawurl string) (UUID, error) {
+	u, err := url.Parse(rawurl)
+	if err != nil {
+		return Nil, err
+	}
+
+	u.Scheme = strings.ToLower(u.Scheme)
+
+	host, port := strings.ToLower(u.Hostname()), u.Port()
+	if (u.Scheme == "http" && port == "80") || (u.Scheme == "https" && port == "443") {
+		port = ""
+	}
+	switch {
+	case port != "":
+		u.Host = net.JoinHostPort(host, port)
+	case strings.Contains(host, ":"):
+		u.Host = "[" + host + "]" // IPv6 literal
+	default:
+		u.Host = host
+	}
+
+	if u.RawQuery != "" {
+		u.RawQuery = u.Query().Encode()
+	}
+
+	return NewV5(NamespaceURL, u.String()), nil
+}
+
 // NewV6 returns a k-sortable UUID based on a timestamp and 48 bits of
 // pseudorandom data. The timestamp in a V6 UUID is the same as V1, with the bit
 // order being adjusted to allow the UUID to be k-sortable.
@@ -98,6 +507,98 @@ func NewV7(p Precision) (UUID, error) {
 	return DefaultGenerator.NewV7(p)
 }
 
+// NewV1At returns a V1 UUID with the timestamp t instead of the current time,
+// for backfilling UUIDs that correspond to historical events. See Gen.NewV1At
+// for details.
+//
+// It returns an error if the default Generator has no NewV1At method.
+func NewV1At(t time.Time) (UUID, error) {
+	g := DefaultGenerator
+	if a, ok := g.(interface {
+		NewV1At(time.Time) (UUID, error)
+	}); ok {
+		return a.NewV1At(t)
+	}
+	return Nil, unsupportedError(g, "NewV1At")
+}
+
+// NewV6At returns a V6 UUID with the timestamp t instead of the current time,
+// for backfilling UUIDs that correspond to historical events. See Gen.NewV6At
+// for details.
+//
+// It returns an error if the default Generator has no NewV6At method.
+func NewV6At(t time.Time) (UUID, error) {
+	g := DefaultGenerator
+	if a, ok := g.(interface {
+		NewV6At(time.Time) (UUID, error)
+	}); ok {
+		return a.NewV6At(t)
+	}
+	return Nil, unsupportedError(g, "NewV6At")
+}
+
+// NewV7At returns a V7 UUID with the timestamp t, at precision p, instead of
+// the current time, for backfilling UUIDs that correspond to historical
+// events. See Gen.NewV7At for details.
+//
+// It returns an error if the default Generator has no NewV7At method.
+func NewV7At(t time.Time, p Precision) (UUID, error) {
+	g := DefaultGenerator
+	if a, ok := g.(interface {
+		NewV7At(time.Time, Precision) (UUID, error)
+	}); ok {
+		return a.NewV7At(t, p)
+	}
+	return Nil, unsupportedError(g, "NewV7At")
+}
+
+// NewV7Batch returns n millisecond precision V7 UUIDs that are strictly
+// increasing, for batch inserts that rely on k-sortability. See Gen.NewV7Batch
+// for details.
+//
+// It returns an error if the default Generator has no NewV7Batch method.
+func NewV7Batch(n int) ([]UUID, error) {
+	g := DefaultGenerator
+	if b, ok := g.(interface {
+		NewV7Batch(int) ([]UUID, error)
+	}); ok {
+		return b.NewV7Batch(n)
+	}
+	return nil, unsupportedError(g, "NewV7Batch")
+}
+
+// NewV7BatchAt returns n millisecond precision V7 UUIDs that all share the
+// timestamp t. Within the batch the seq field counts up from zero, so the
+// UUIDs sort in the order they are returned. At most 4096 UUIDs may be
+// generated in a single batch.
+//
+// It returns an error if the default Generator has no NewV7BatchAt method.
+func NewV7BatchAt(t time.Time, n int) ([]UUID, error) {
+	g := DefaultGenerator
+	if b, ok := g.(interface {
+		NewV7BatchAt(time.Time, int) ([]UUID, error)
+	}); ok {
+		return b.NewV7BatchAt(t, n)
+	}
+	return nil, unsupportedError(g, "NewV7BatchAt")
+}
+
+// GenerateV7Over returns n millisecond precision V7 UUIDs with timestamps
+// evenly spread across the inclusive range [start, end], sorted ascending, for
+// seeding tests with time-distributed data. UUIDs falling within the same
+// millisecond are ordered by their seq field.
+//
+// It returns an error if the default Generator has no GenerateV7Over method.
+func GenerateV7Over(start, end time.Time, n int) ([]UUID, error) {
+	g := DefaultGenerator
+	if o, ok := g.(interface {
+		GenerateV7Over(time.Time, time.Time, int) ([]UUID, error)
+	}); ok {
+		return o.GenerateV7Over(start, end, n)
+	}
+	return nil, unsupportedError(g, "GenerateV7Over")
+}
+
 // Generator provides an interface for generating UUIDs.
 type Generator interface {
 	NewV1() (UUID, error)
@@ -120,14 +621,85 @@ type Generator interface {
 // to obfuscate their MAC address, and so we recommend using NewGen() to create
 // a new generator.
 type Gen struct {
+	// RandomNodePreferred causes V1 UUIDs to always use a random node with
+	// the multicast bit set, as permitted by RFC-4122 section 4.5, instead
+	// of the hardware address returned by the HWAddrFunc. This avoids
+	// exposing the MAC address of the machine generating UUIDs, at the cost
+	// of relying on 47 random bits rather than a globally unique hardware
+	// address to keep UUIDs from different nodes distinct.
+	//
+	// The node is chosen once per generator, so this must be set before the
+	// first V1 UUID is generated. DefaultRandomNodePreferred sets it for
+	// every generator, including the one used by the package-level functions.
+	RandomNodePreferred bool
+
+	// MaxFutureSkew, if positive, causes NewV7 to return an error instead of a
+	// UUID when the clock has jumped forward by more than MaxFutureSkew, which
+	// protects downstream systems that reject future-dated IDs.
+	//
+	// A jump is measured as how much further the clock advanced than the
+	// monotonic clock did since the last V7 UUID, so an idle period is not a
+	// jump. The reference point only moves when a UUID is generated: every
+	// call fails until the clock comes back to within MaxFutureSkew of the
+	// expected time. The first V7 UUID generated is never rejected.
+	MaxFutureSkew time.Duration
+
+	// UniqueTail causes NewV7 to remember the last 8 bytes of each V7 UUID
+	// generated within the current millisecond and to redraw the random bits
+	// of any UUID whose tail has already been used, so that no two V7 UUIDs
+	// from the generator share a tail within a millisecond even if the
+	// random source repeats. For microsecond and nanosecond precision the
+	// tails are tracked per value of the 48-bit timestamp prefix instead. An
+	// error is returned if a unique tail cannot be found after a few
+	// attempts.
+	//
+	// The overhead is a map lookup per UUID and a lock that serializes calls
+	// to NewV7 on the generator, so it is best reserved for generators whose
+	// random source is not trusted to be collision free.
+	//
+	// With MonotonicRandom every bit after the timestamp determines the
+	// order of the UUIDs, so none of them are redrawn: the strictly
+	// increasing counter already makes the tails unique, and UniqueTail has
+	// no effect on millisecond precision UUIDs.
+	UniqueTail bool
+
+	// MonotonicRandom causes NewV7 with MillisecondPrecision to implement
+	// method 2 of RFC 9562 section 6.2: within the same millisecond, the 74
+	// bits following the timestamp (the seq field and the random bits) are
+	// incremented by a random step of up to 2^32 instead of counting the seq
+	// field up from zero, so UUIDs from the generator are strictly increasing
+	// without limiting them to 4096 per millisecond. If the clock moves
+	// backwards the timestamp of the last UUID is reused. The other
+	// precisions are not affected.
+	MonotonicRandom bool
+
+	// SubMillisecondFraction causes NewV7 with MillisecondPrecision to
+	// implement method 3 of RFC 9562 section 6.2: the UUID starts with the
+	// RFC 9562 48-bit Unix timestamp in milliseconds, and the 12-bit rand_a
+	// field holds the fraction of the millisecond, scaled to 4096 steps of
+	// about 244 nanoseconds. If the fraction has not advanced since the last
+	// UUID, or the clock has moved backwards, the previous value is
+	// incremented instead, so UUIDs from the generator are strictly
+	// increasing. Use TimeFromV7Fraction to decode their time.
+	//
+	// The layout differs from that of the revision 02 draft used by the rest
+	// of this package, so these UUIDs are not ordered relative to V7 UUIDs
+	// generated without SubMillisecondFraction, for example by NewV7Batch.
+	// It takes precedence over MonotonicRandom, and the other precisions are
+	// not affected.
+	SubMillisecondFraction bool
+
 	clockSequenceOnce sync.Once
 	hardwareAddrOnce  sync.Once
 	storageMutex      sync.Mutex
 
 	rand io.Reader
 
-	epochFunc     epochFunc
+	epochFunc     EpochFunc
 	hwAddrFunc    HWAddrFunc
+	stableStorage StableStorageFunc
+	nodeID        *[6]byte
+	v8Epoch       time.Time
 	lastTime      uint64
 	clockSequence uint16
 	hardwareAddr  [6]byte
@@ -135,6 +707,20 @@ type Gen struct {
 	v7LastTime      uint64
 	v7LastSubsec    uint64
 	v7ClockSequence uint16
+	v7RandHi        uint16        // MonotonicRandom seq field
+	v7RandLo        uint64        // MonotonicRandom random bits
+	v7FracLast      uint64        // SubMillisecondFraction unix ms << 12 | fraction
+	v7Lead          time.Duration // how far NewV7Batch ran ahead of the clock
+
+	// MaxFutureSkew state: the last accepted epochFunc reading and the
+	// monotonic clock at that point. monoFunc is time.Now if nil.
+	v7SkewWall time.Time
+	v7SkewMono time.Time
+	monoFunc   EpochFunc
+
+	v7TailMutex  sync.Mutex
+	v7TailPrefix uint64
+	v7Tails      map[[8]byte]struct{}
 }
 
 // interface check -- build will fail if *Gen doesn't satisfy Generator
@@ -165,6 +751,129 @@ func NewGenWithHWAF(hwaf HWAddrFunc) *Gen {
 	}
 }
 
+// GenOption is a function type that can be used to configure a Gen generator.
+type GenOption func(*Gen)
+
+// NewGenWithOptions returns a new instance of Gen with the options provided.
+// Most people should use NewGen() or NewGenWithHWAF() instead.
+//
+// To customize the generator, you can pass in one or more GenOption functions.
+// For example:
+//
+//	gen := NewGenWithOptions(
+//		WithHWAddrFunc(myHWAddrFunc),
+//		WithEpochFunc(myEpochFunc),
+//		WithRandomReader(myRandomReader),
+//	)
+//
+// NewGenWithOptions(WithHWAddrFunc(myHWAddrFunc)) is equivalent to calling
+// NewGenWithHWAF(myHWAddrFunc), and NewGenWithOptions() is equivalent to
+// calling NewGen().
+func NewGenWithOptions(opts ...GenOption) *Gen {
+	g := NewGen()
+	for _, opt := range opts {
+		opt(g)
+	}
+	return g
+}
+
+// WithHWAddrFunc is a GenOption that allows you to provide your own HWAddrFunc
+// function. When this option is nil, the defaultHWAddrFunc is used.
+func WithHWAddrFunc(hwaf HWAddrFunc) GenOption {
+	return func(g *Gen) {
+		if hwaf == nil {
+			hwaf = defaultHWAddrFunc
+		}
+		g.hwAddrFunc = hwaf
+	}
+}
+
+// WithEpochFunc is a GenOption that allows you to provide your own EpochFunc
+// function, which is used as the clock for the time-based UUIDs. This makes
+// it possible to generate deterministic UUIDs in tests. When this option is
+// nil, time.Now is used.
+func WithEpochFunc(epochf EpochFunc) GenOption {
+	return func(g *Gen) {
+		if epochf == nil {
+			epochf = time.Now
+		}
+		g.epochFunc = epochf
+	}
+}
+
+// WithRandomReader is a GenOption that allows you to provide your own random
+// reader, which is used for V4 and V7 UUIDs, the clock sequence, and random
+// nodes. This allows UUIDs to be generated in environments without a
+// system random source. When this option is nil, crypto/rand.Reader is used.
+func WithRandomReader(reader io.Reader) GenOption {
+	return func(g *Gen) {
+		if reader == nil {
+			reader = rand.Reader
+		}
+		g.rand = reader
+	}
+}
+
+// WithStableStorage is a GenOption that persists the V1 and V6 clock state
+// with f, as recommended by RFC-4122 section 4.2.1, so that UUIDs remain
+// unique across restarts of the process and clock rollbacks that happen while
+// it is not running. Before generating each V1 or V6 UUID the generator
+// passes the stored state to its update function: if the clock has not moved
+// past the stored timestamp the stored clock sequence is incremented,
+// otherwise it is reused. The generator's random clock sequence is only used
+// when nothing has been stored yet.
+//
+// Since f is called for every V1 and V6 UUID, its cost bounds the rate at
+// which they can be generated. An error returned by f is returned by NewV1 or
+// NewV6. When this option is nil, the clock state is only kept in memory.
+func WithStableStorage(f StableStorageFunc) GenOption {
+	return func(g *Gen) {
+		g.stableStorage = f
+	}
+}
+
+// WithNodeID is a GenOption that pins the node of V1 and V6 UUIDs to node,
+// instead of detecting a hardware address for V1 and using random data for
+// V6. This is useful in containers, where virtual MAC addresses may change
+// between restarts or be duplicated across hosts. It takes precedence over
+// RandomNodePreferred and the HWAddrFunc, which is never called.
+func WithNodeID(node [6]byte) GenOption {
+	return func(g *Gen) {
+		g.nodeID = &node
+	}
+}
+
+// WithRandomNodeID is a GenOption that sets RandomNodePreferred, so that V1
+// UUIDs use a random node with the multicast bit set, as permitted by
+// RFC-4122 section 4.5, instead of the machine's MAC address. V6 UUIDs always
+// use a random node unless WithNodeID is given.
+func WithRandomNodeID() GenOption {
+	return func(g *Gen) {
+		g.RandomNodePreferred = true
+	}
+}
+
+// WithSubMillisecondFraction is a GenOption that sets SubMillisecondFraction,
+// so that millisecond precision V7 UUIDs encode the fraction of the
+// millisecond in their rand_a field as described by method 3 of RFC 9562
+// section 6.2.
+func WithSubMillisecondFraction() GenOption {
+	return func(g *Gen) {
+		g.SubMillisecondFraction = true
+	}
+}
+
+// WithV8Epoch is a GenOption that sets the epoch of the timestamps of V8
+// UUIDs generated by Gen.NewV8Epoch, for example the founding date of a
+// company. A recent epoch extends the range of dates that the 48-bit
+// timestamp can represent, about 8900 years, and makes the timestamps
+// smaller. When this option is not given the Unix epoch is used.
+func WithV8Epoch(epoch time.Time) GenOption {
+	return func(g *Gen) {
+		g.v8Epoch = epoch
+	}
+}
+
 // NewV1 returns a UUID based on the current timestamp and MAC address.
 func (g *Gen) NewV1() (UUID, error) {
 	u := UUID{}
@@ -173,9 +882,7 @@ func (g *Gen) NewV1() (UUID, error) {
 	if err != nil {
 		return Nil, err
 	}
-	binary.BigEndian.PutUint32(u[0:], uint32(timeNow))
-	binary.BigEndian.PutUint16(u[4:], uint16(timeNow>>32))
-	binary.BigEndian.PutUint16(u[6:], uint16(timeNow>>48))
+	putV1Timestamp(&u, timeNow)
 	binary.BigEndian.PutUint16(u[8:], clockSeq)
 
 	hardwareAddr, err := g.getHardwareAddr()
@@ -200,9 +907,16 @@ func (g *Gen) NewV3(ns UUID, name string) UUID {
 }
 
 // NewV4 returns a randomly generated UUID.
+//
+// When the generator uses crypto/rand, which is the default, and the pool has
+// been enabled with EnableRandPool, random bytes are read in blocks and cached
+// per-P so that concurrent calls rarely contend.
 func (g *Gen) NewV4() (UUID, error) {
+	if g.rand != rand.Reader || !randPoolEnabled() {
+		return NewV4FromReader(g.rand)
+	}
 	u := UUID{}
-	if _, err := io.ReadFull(g.rand, u[:]); err != nil {
+	if err := readEntropy(u[:]); err != nil {
 		return Nil, err
 	}
 	u.SetVersion(V4)
@@ -211,6 +925,39 @@ func (g *Gen) NewV4() (UUID, error) {
 	return u, nil
 }
 
+// NewV4Batch returns n randomly generated UUIDs, reading the random bytes for
+// all of them from the generator's random source in a single read.
+func (g *Gen) NewV4Batch(n int) ([]UUID, error) {
+	if n < 0 {
+		return nil, fmt.Errorf("uuid: invalid UUID count %d", n)
+	}
+	us := make([]UUID, n)
+	if err := g.FillV4(us); err != nil {
+		return nil, err
+	}
+	return us, nil
+}
+
+// FillV4 fills dst with randomly generated UUIDs, reading the random bytes for
+// all of them from the generator's random source in a single read. On error
+// the contents of dst are unspecified.
+func (g *Gen) FillV4(dst []UUID) error {
+	if len(dst) == 0 {
+		return nil
+	}
+	b := make([]byte, len(dst)*Size)
+	if _, err := io.ReadFull(g.rand, b); err != nil {
+		return err
+	}
+	for i := range dst {
+		u := &dst[i]
+		copy(u[:], b[i*Size:])
+		u.SetVersion(V4)
+		u.SetVariant(VariantRFC4122)
+	}
+	return nil
+}
+
 // NewV5 returns a UUID based on SHA-1 hash of the namespace UUID and name.
 func (g *Gen) NewV5(ns UUID, name string) UUID {
 	u := newFromHash(sha1.New(), ns, name)
@@ -222,7 +969,9 @@ func (g *Gen) NewV5(ns UUID, name string) UUID {
 
 // NewV6 returns a k-sortable UUID based on a timestamp and 48 bits of
 // pseudorandom data. The timestamp in a V6 UUID is the same as V1, with the bit
-// order being adjusted to allow the UUID to be k-sortable.
+// order being adjusted to allow the UUID to be k-sortable. If the generator
+// was created with WithNodeID, that node is used instead of the pseudorandom
+// data.
 //
 // This is implemented based on revision 02 of the Peabody UUID draft, and may
 // be subject to change pending further revisions. Until the final specification
@@ -232,7 +981,9 @@ func (g *Gen) NewV5(ns UUID, name string) UUID {
 func (g *Gen) NewV6() (UUID, error) {
 	var u UUID
 
-	if _, err := io.ReadFull(g.rand, u[10:]); err != nil {
+	if g.nodeID != nil {
+		copy(u[10:], g.nodeID[:])
+	} else if _, err := io.ReadFull(g.rand, u[10:]); err != nil {
 		return Nil, err
 	}
 
@@ -241,10 +992,8 @@ func (g *Gen) NewV6() (UUID, error) {
 		return Nil, err
 	}
 
-	binary.BigEndian.PutUint32(u[0:], uint32(timeNow>>28))   // set time_high
-	binary.BigEndian.PutUint16(u[4:], uint16(timeNow>>12))   // set time_mid
-	binary.BigEndian.PutUint16(u[6:], uint16(timeNow&0xfff)) // set time_low (minus four version bits)
-	binary.BigEndian.PutUint16(u[8:], clockSeq&0x3fff)       // set clk_seq_hi_res (minus two variant bits)
+	putV6Timestamp(&u, timeNow)
+	binary.BigEndian.PutUint16(u[8:], clockSeq&0x3fff) // set clk_seq_hi_res (minus two variant bits)
 
 	u.SetVersion(V6)
 	u.SetVariant(VariantRFC4122)
@@ -270,6 +1019,9 @@ func (g *Gen) getClockSequence() (uint64, uint16, error) {
 	defer g.storageMutex.Unlock()
 
 	timeNow := g.getEpoch()
+	if g.stableStorage != nil {
+		return g.updateStableStorage(timeNow)
+	}
 	// Clock didn't change since last UUID generation.
 	// Should increase clock sequence.
 	if timeNow <= g.lastTime {
@@ -280,6 +1032,34 @@ func (g *Gen) getClockSequence() (uint64, uint16, error) {
 	return timeNow, g.clockSequence, nil
 }
 
+// updateStableStorage advances the clock state kept by g.stableStorage to
+// timeNow and returns the new clock sequence. The caller must hold
+// g.storageMutex.
+func (g *Gen) updateStableStorage(timeNow uint64) (uint64, uint16, error) {
+	var next ClockState
+	updated := false
+	err := g.stableStorage(func(s ClockState) ClockState {
+		if s == (ClockState{}) {
+			s.ClockSequence = g.clockSequence
+		} else if timeNow <= s.LastTime {
+			s.ClockSequence++
+		}
+		s.LastTime = timeNow
+		next, updated = s, true
+		return s
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+	if !updated {
+		return 0, 0, errors.New("uuid: stable storage did not update the clock state")
+	}
+	g.lastTime = next.LastTime
+	g.clockSequence = next.ClockSequence
+
+	return timeNow, next.ClockSequence, nil
+}
+
 // Precision is used to configure the V7 generator, to specify how precise the
 // timestamp within the UUID should be.
 type Precision byte
@@ -342,6 +1122,11 @@ func (g *Gen) NewV7(p Precision) (UUID, error) {
 	var u UUID
 	var err error
 
+	if g.UniqueTail {
+		g.v7TailMutex.Lock()
+		defer g.v7TailMutex.Unlock()
+	}
+
 	switch p {
 	case NanosecondPrecision:
 		u, err = g.newV7Nano()
@@ -350,7 +1135,13 @@ func (g *Gen) NewV7(p Precision) (UUID, error) {
 		u, err = g.newV7Micro()
 
 	case MillisecondPrecision:
-		u, err = g.newV7Milli()
+		if g.SubMillisecondFraction {
+			u, err = g.newV7MilliFraction()
+		} else if g.MonotonicRandom {
+			u, err = g.newV7MilliMonotonic()
+		} else {
+			u, err = g.newV7Milli()
+		}
 
 	default:
 		panic(fmt.Sprintf("unknown precision value %d", p))
@@ -363,9 +1154,54 @@ func (g *Gen) NewV7(p Precision) (UUID, error) {
 	u.SetVersion(V7)
 	u.SetVariant(VariantRFC4122)
 
+	// redrawing any bits of a MonotonicRandom UUID would break its order
+	monotonic := p == MillisecondPrecision && g.MonotonicRandom && !g.SubMillisecondFraction
+	if g.UniqueTail && !monotonic {
+		if err := g.uniqueV7Tail(&u, p); err != nil {
+			return Nil, err
+		}
+	}
+
 	return u, nil
 }
 
+// maxTailAttempts is the number of times uniqueV7Tail draws new random bits
+// before giving up.
+const maxTailAttempts = 8
+
+// uniqueV7Tail redraws the random bits of u until its last 8 bytes differ
+// from those of every V7 UUID generated with the same 48-bit timestamp
+// prefix. The caller must hold g.v7TailMutex.
+func (g *Gen) uniqueV7Tail(u *UUID, p Precision) error {
+	off := 8 // start of the random bits
+	switch p {
+	case MicrosecondPrecision:
+		off = 10
+	case NanosecondPrecision:
+		off = 11
+	}
+
+	prefix := binary.BigEndian.Uint64(u[:8]) >> 16
+	if g.v7Tails == nil || prefix != g.v7TailPrefix {
+		g.v7Tails = make(map[[8]byte]struct{})
+		g.v7TailPrefix = prefix
+	}
+
+	for i := 0; i < maxTailAttempts; i++ {
+		var tail [8]byte
+		copy(tail[:], u[8:])
+		if _, ok := g.v7Tails[tail]; !ok {
+			g.v7Tails[tail] = struct{}{}
+			return nil
+		}
+		if _, err := io.ReadFull(g.rand, u[off:]); err != nil {
+			return err
+		}
+		u.SetVariant(VariantRFC4122)
+	}
+	return fmt.Errorf("uuid: no unique V7 tail found after %d attempts", maxTailAttempts)
+}
+
 func (g *Gen) newV7Milli() (UUID, error) {
 	var u UUID
 
@@ -378,17 +1214,324 @@ func (g *Gen) newV7Milli() (UUID, error) {
 		return Nil, err
 	}
 
-	msec := (nano / 1000000) & 0xfff
+	putV7Milli(&u, sec, nano/1000000, seq)
 
-	d := (sec << 28)           // set unixts field
-	d |= (msec << 16)          // set msec field
-	d |= (uint64(seq) & 0xfff) // set seq field
+	return u, nil
+}
 
-	binary.BigEndian.PutUint64(u[:], d)
+// newV7MilliMonotonic implements MonotonicRandom.
+func (g *Gen) newV7MilliMonotonic() (UUID, error) {
+	var b [14]byte // 74 random bits followed by a 32-bit step
+	if _, err := io.ReadFull(g.rand, b[:]); err != nil {
+		return Nil, err
+	}
+
+	g.storageMutex.Lock()
+	defer g.storageMutex.Unlock()
+
+	tn := g.epochFunc()
+	if err := g.checkV7Skew(tn); err != nil {
+		return Nil, err
+	}
+	unix := uint64(tn.Unix())
+	nsec := uint64(tn.Nanosecond())
+
+	first := g.v7LastTime == 0 && g.v7LastSubsec == 0
+	if first || unix > g.v7LastTime || (unix == g.v7LastTime && nsec/1000000 > g.v7LastSubsec/1000000) {
+		g.v7LastTime = unix
+		g.v7LastSubsec = nsec
+		g.v7RandHi = binary.BigEndian.Uint16(b[0:]) & maxSeq12
+		g.v7RandLo = binary.BigEndian.Uint64(b[2:]) & (1<<62 - 1)
+	} else {
+		hi := g.v7RandHi
+		lo := g.v7RandLo + uint64(binary.BigEndian.Uint32(b[10:])) + 1
+		if lo >= 1<<62 {
+			lo -= 1 << 62
+			hi++
+		}
+		if hi > maxSeq12 {
+			return Nil, errors.New("generating millisecond precision UUIDv7s too fast: monotonic random value would overflow")
+		}
+		g.v7RandHi = hi
+		g.v7RandLo = lo
+	}
+	g.v7ClockSequence = g.v7RandHi // so that NewV7Batch continues after u
+
+	var u UUID
+	binary.BigEndian.PutUint64(u[8:], g.v7RandLo)
+	putV7Milli(&u, g.v7LastTime, g.v7LastSubsec/1000000, g.v7RandHi)
 
 	return u, nil
 }
 
+// newV7MilliFraction implements SubMillisecondFraction.
+func (g *Gen) newV7MilliFraction() (UUID, error) {
+	var u UUID
+	if _, err := io.ReadFull(g.rand, u[8:]); err != nil {
+		return Nil, err
+	}
+
+	g.storageMutex.Lock()
+	defer g.storageMutex.Unlock()
+
+	tn := g.epochFunc()
+	if err := g.checkV7Skew(tn); err != nil {
+		return Nil, err
+	}
+	if tn.Unix() < 0 {
+		return Nil, fmt.Errorf("uuid: time %v out of range for V7", tn)
+	}
+	nsec := uint64(tn.Nanosecond())
+	ts := (uint64(tn.Unix())*1000+nsec/1000000)<<12 | nsec%1000000*4096/1000000
+	if ts <= g.v7FracLast {
+		ts = g.v7FracLast + 1 // carries into the millisecond
+	}
+	if ts >= 1<<60 {
+		return Nil, fmt.Errorf("uuid: time %v out of range for V7", tn)
+	}
+	ms := ts >> 12
+	g.v7FracLast = ts
+	g.v7LastTime = ms / 1000
+	g.v7LastSubsec = ms%1000*1000000 + (ts&0xfff)*1000000/4096
+
+	// 48-bit unix_ts_ms, 4-bit version (set by NewV7), 12-bit rand_a
+	binary.BigEndian.PutUint64(u[:], ts>>12<<16|ts&0xfff)
+
+	return u, nil
+}
+
+// atGen returns a generator whose clock is fixed at t and which shares the
+// random source and node of g, for the NewVxAt methods. The returned
+// generator has its own clock state, so g's is neither used nor advanced.
+func (g *Gen) atGen(t time.Time) *Gen {
+	return &Gen{
+		epochFunc:           func() time.Time { return t },
+		hwAddrFunc:          g.hwAddrFunc,
+		rand:                g.rand,
+		nodeID:              g.nodeID,
+		RandomNodePreferred: g.RandomNodePreferred,
+	}
+}
+
+// checkV1Time returns an error if t cannot be represented in a V1 or V6 UUID.
+func checkV1Time(t time.Time) error {
+	if t.Before(time.Unix(0, -1<<63)) || t.After(time.Unix(0, 1<<63-1)) {
+		return fmt.Errorf("uuid: time %v out of range for V1 and V6", t)
+	}
+	return nil
+}
+
+// NewV1At returns a V1 UUID with the timestamp t instead of the current time,
+// for backfilling UUIDs that correspond to historical events. The node is the
+// same as for NewV1, but the generator's clock state is neither used nor
+// advanced: every UUID gets a new random clock sequence, so UUIDs generated
+// for the same t are distinct with high probability but not guaranteed to
+// be. An error is returned if t is outside of the years 1678 to 2262.
+func (g *Gen) NewV1At(t time.Time) (UUID, error) {
+	if err := checkV1Time(t); err != nil {
+		return Nil, err
+	}
+	node, err := g.getHardwareAddr()
+	if err != nil {
+		return Nil, err
+	}
+	ag := g.atGen(t)
+	var n [6]byte
+	copy(n[:], node)
+	ag.nodeID = &n
+	return ag.NewV1()
+}
+
+// NewV6At returns a V6 UUID with the timestamp t instead of the current time,
+// for backfilling UUIDs that correspond to historical events. As with NewV1At
+// the generator's clock state is neither used nor advanced, and every UUID
+// gets a new random clock sequence. An error is returned if t is outside of
+// the years 1678 to 2262.
+func (g *Gen) NewV6At(t time.Time) (UUID, error) {
+	if err := checkV1Time(t); err != nil {
+		return Nil, err
+	}
+	return g.atGen(t).NewV6()
+}
+
+// NewV7At returns a V7 UUID with the timestamp t, at precision p, instead of
+// the current time, for backfilling UUIDs that correspond to historical
+// events. The generator's V7 clock sequence is neither used nor advanced, so
+// the seq field of the UUID is zero and UUIDs generated for the same t are
+// only distinguished by their random bits. An error is returned if t cannot
+// be represented in a V7 UUID. NewV7At panics if p is not a known Precision.
+func (g *Gen) NewV7At(t time.Time, p Precision) (UUID, error) {
+	if t.Unix() < 0 || t.Unix() >= 1<<36 {
+		return Nil, fmt.Errorf("uuid: time %v out of range for V7", t)
+	}
+	return g.atGen(t).NewV7(p)
+}
+
+// NewV7Batch returns n millisecond precision V7 UUIDs that are strictly
+// increasing, continuing the generator's V7 clock sequence. The UUIDs start at
+// the current millisecond, or at the millisecond of the last V7 UUID if the
+// clock has not advanced past it, and are numbered by their seq field. When
+// the 4096 seq values of a millisecond are used up the batch moves on to the
+// next millisecond, so the timestamps of large batches may run slightly ahead
+// of the clock. The random portion of every UUID is filled from a single read
+// of the generator's random source. Later calls to NewV7 with
+// MillisecondPrecision sort after the batch.
+func (g *Gen) NewV7Batch(n int) ([]UUID, error) {
+	if n < 0 {
+		return nil, fmt.Errorf("uuid: invalid V7 batch size %d", n)
+	}
+	if n == 0 {
+		return []UUID{}, nil
+	}
+
+	tails := make([]byte, n*8)
+	if _, err := io.ReadFull(g.rand, tails); err != nil {
+		return nil, err
+	}
+
+	g.storageMutex.Lock()
+	defer g.storageMutex.Unlock()
+
+	tn := g.epochFunc()
+	if tn.Unix() < 0 || tn.Unix() >= 1<<36 {
+		return nil, fmt.Errorf("uuid: time %v out of range for V7", tn)
+	}
+	ms := uint64(tn.Unix())*1000 + uint64(tn.Nanosecond()/1000000)
+	clockMs := ms
+	var seq uint64
+	if g.v7LastTime != 0 || g.v7LastSubsec != 0 {
+		if last := g.v7LastTime*1000 + g.v7LastSubsec/1000000; ms <= last {
+			ms, seq = last, uint64(g.v7ClockSequence)+1
+		}
+	}
+
+	us := make([]UUID, n)
+	for i := range us {
+		if seq > maxSeq12 {
+			ms, seq = ms+1, 0
+		}
+		u := &us[i]
+		copy(u[8:], tails[i*8:])
+		putV7Milli(u, ms/1000, ms%1000, uint16(seq))
+		u.SetVersion(V7)
+		u.SetVariant(VariantRFC4122)
+		seq++
+	}
+	if ms/1000 >= 1<<36 {
+		return nil, fmt.Errorf("uuid: time %v out of range for V7", tn)
+	}
+
+	g.v7LastTime = ms / 1000
+	g.v7LastSubsec = ms % 1000 * 1000000
+	g.v7ClockSequence = uint16(seq - 1)
+	g.v7Lead = 0
+	if ms > clockMs {
+		g.v7Lead = time.Duration(ms-clockMs) * time.Millisecond
+	}
+	// With MonotonicRandom the next increment carries into the seq field,
+	// so that NewV7 also continues after the batch.
+	g.v7RandHi = g.v7ClockSequence
+	g.v7RandLo = 1<<62 - 1
+
+	return us, nil
+}
+
+// NewV7BatchAt returns n millisecond precision V7 UUIDs that all share the
+// timestamp t, with the seq field set to each UUID's index in the batch. The
+// random portion of every UUID is filled from a single read of the generator's
+// random source. It returns an error if n is negative or greater than 4096, or
+// if t cannot be represented in a V7 UUID.
+//
+// The batch does not advance the generator's V7 clock sequence, so UUIDs
+// returned by NewV7 within the same millisecond may sort before or after it.
+func (g *Gen) NewV7BatchAt(t time.Time, n int) ([]UUID, error) {
+	if n < 0 || n > maxSeq12+1 {
+		return nil, fmt.Errorf("uuid: invalid V7 batch size %d", n)
+	}
+	sec := t.Unix()
+	if sec < 0 || sec >= 1<<36 {
+		return nil, fmt.Errorf("uuid: time %v out of range for V7", t)
+	}
+	msec := uint64(t.Nanosecond() / 1000000)
+
+	tails := make([]byte, n*8)
+	if _, err := io.ReadFull(g.rand, tails); err != nil {
+		return nil, err
+	}
+
+	us := make([]UUID, n)
+	for i := range us {
+		u := &us[i]
+		copy(u[8:], tails[i*8:])
+		putV7Milli(u, uint64(sec), msec, uint16(i))
+		u.SetVersion(V7)
+		u.SetVariant(VariantRFC4122)
+	}
+
+	return us, nil
+}
+
+// GenerateV7Over returns n millisecond precision V7 UUIDs with timestamps
+// evenly spread across the inclusive range [start, end], sorted ascending. The
+// first UUID is at start and, if n > 1, the last at end, truncated to the
+// millisecond. UUIDs falling within the same millisecond are numbered by their
+// seq field; an error is returned if more than 4096 of them would share one.
+// An error is also returned if n is negative, end is before start, or either
+// time cannot be represented in a V7 UUID.
+//
+// The generator's V7 clock sequence is neither used nor advanced.
+func (g *Gen) GenerateV7Over(start, end time.Time, n int) ([]UUID, error) {
+	if n < 0 {
+		return nil, fmt.Errorf("uuid: invalid UUID count %d", n)
+	}
+	if end.Before(start) {
+		return nil, fmt.Errorf("uuid: end time %v is before start time %v", end, start)
+	}
+	for _, t := range []time.Time{start, end} {
+		if t.Unix() < 0 || t.Unix() >= 1<<36 {
+			return nil, fmt.Errorf("uuid: time %v out of range for V7", t)
+		}
+	}
+	span := end.Sub(start)
+	if span == time.Duration(1<<63-1) {
+		return nil, fmt.Errorf("uuid: time range from %v to %v is too long", start, end)
+	}
+
+	tails := make([]byte, n*8)
+	if _, err := io.ReadFull(g.rand, tails); err != nil {
+		return nil, err
+	}
+
+	us := make([]UUID, n)
+	var lastMs uint64
+	var seq uint16
+	for i := range us {
+		t := start
+		if n > 1 {
+			steps := time.Duration(n - 1)
+			t = start.Add(span/steps*time.Duration(i) + span%steps*time.Duration(i)/steps)
+		}
+		ms := uint64(t.Unix())*1000 + uint64(t.Nanosecond()/1000000)
+		switch {
+		case i == 0 || ms != lastMs:
+			seq = 0
+		case seq >= maxSeq12:
+			return nil, fmt.Errorf("uuid: more than %d UUIDs in millisecond %v", maxSeq12+1, t.Truncate(time.Millisecond))
+		default:
+			seq++
+		}
+		lastMs = ms
+
+		u := &us[i]
+		copy(u[8:], tails[i*8:])
+		putV7Milli(u, ms/1000, ms%1000, seq)
+		u.SetVersion(V7)
+		u.SetVariant(VariantRFC4122)
+	}
+
+	return us, nil
+}
+
 func (g *Gen) newV7Micro() (UUID, error) {
 	var u UUID
 
@@ -442,12 +1585,41 @@ func (g *Gen) newV7Nano() (UUID, error) {
 	return u, nil
 }
 
+// maxV7Lead is how far the clock may fall behind the last V7 UUID, beyond any
+// lead of a NewV7Batch, before getV7ClockSequence treats it as a backwards
+// step and stops reusing the last timestamp.
+const maxV7Lead = 10 * time.Millisecond
+
 const (
 	maxSeq14 = (1 << 14) - 1
 	maxSeq12 = (1 << 12) - 1
 	maxSeq8  = (1 << 8) - 1
 )
 
+// checkV7Skew returns an error if tn is more than MaxFutureSkew ahead of
+// where the monotonic clock says it should be, given the clock reading of the
+// last V7 UUID. A rejected tn leaves that reference point unchanged, so the
+// jump keeps being reported. The caller must hold g.storageMutex.
+func (g *Gen) checkV7Skew(tn time.Time) error {
+	if g.MaxFutureSkew <= 0 {
+		return nil
+	}
+	monoFunc := g.monoFunc
+	if monoFunc == nil {
+		monoFunc = time.Now
+	}
+	mono := monoFunc()
+	tn = tn.Round(0) // strip any monotonic reading, only the wall time counts
+	if !g.v7SkewMono.IsZero() {
+		elapsed := mono.Sub(g.v7SkewMono)
+		if skew := tn.Sub(g.v7SkewWall) - elapsed; skew > g.MaxFutureSkew {
+			return fmt.Errorf("uuid: clock jumped %v ahead of the monotonic clock since the last V7 UUID, exceeding MaxFutureSkew of %v", skew, g.MaxFutureSkew)
+		}
+	}
+	g.v7SkewWall, g.v7SkewMono = tn, mono
+	return nil
+}
+
 // getV7ClockSequence returns the unix epoch, nanoseconds of current second, and
 // the sequence for V7 UUIDs.
 func (g *Gen) getV7ClockSequence(p Precision) (epoch uint64, nano uint64, seq uint16, err error) {
@@ -458,58 +1630,53 @@ func (g *Gen) getV7ClockSequence(p Precision) (epoch uint64, nano uint64, seq ui
 	unix := uint64(tn.Unix())
 	nsec := uint64(tn.Nanosecond())
 
+	if err := g.checkV7Skew(tn); err != nil {
+		return 0, 0, 0, err
+	}
+
 	// V7 UUIDs have more precise requirements around how the clock sequence
 	// value is generated and used. Specifically they require that the sequence
 	// be zero, unless we've already generated a UUID within this unit of time
 	// (millisecond, microsecond, or nanosecond) at which point you should
-	// increment the sequence. Likewise if time has warped backwards for some reason (NTP
-	// adjustment?), we also increment the clock sequence to reduce the risk of a
-	// collision.
-	switch {
-	case unix < g.v7LastTime:
-		g.v7ClockSequence++
-
-	case unix > g.v7LastTime:
-		g.v7ClockSequence = 0
-
-	case unix == g.v7LastTime:
-		switch p {
-		case NanosecondPrecision:
-			if nsec <= g.v7LastSubsec {
-				if g.v7ClockSequence >= maxSeq8 {
-					return 0, 0, 0, errors.New("generating nanosecond precision UUIDv7s too fast: internal clock sequence would roll over")
-				}
-
-				g.v7ClockSequence++
-			} else {
-				g.v7ClockSequence = 0
-			}
-
-		case MicrosecondPrecision:
-			if nsec/1000 <= g.v7LastSubsec/1000 {
-				if g.v7ClockSequence >= maxSeq14 {
-					return 0, 0, 0, errors.New("generating microsecond precision UUIDv7s too fast: internal clock sequence would roll over")
-				}
+	// increment the sequence. The last timestamp is a floor: if the clock has
+	// not moved past it, either because we're still in the same unit of time,
+	// the clock has jittered back by less than maxV7Lead, or a batch ran ahead
+	// of the clock, the last timestamp is reused and the sequence is
+	// incremented so that UUIDs from the generator are strictly increasing.
+	// If time has warped further backwards for some reason (NTP adjustment?)
+	// the new time is used instead, and we still increment the clock sequence
+	// to reduce the risk of a collision, rather than exhausting the sequence
+	// while waiting for the clock to catch up.
+	var div uint64
+	var maxSeq uint16
+	switch p {
+	case NanosecondPrecision:
+		div, maxSeq = 1, maxSeq8
+	case MicrosecondPrecision:
+		div, maxSeq = 1000, maxSeq14
+	case MillisecondPrecision:
+		div, maxSeq = 1000000, maxSeq12
+	default:
+		panic(fmt.Sprintf("unknown precision value %d", p))
+	}
 
-				g.v7ClockSequence++
-			} else {
-				g.v7ClockSequence = 0
-			}
+	last := time.Unix(int64(g.v7LastTime), int64(g.v7LastSubsec))
+	switch {
+	case last.Sub(tn) > maxV7Lead+g.v7Lead:
+		g.v7ClockSequence = (g.v7ClockSequence + 1) & maxSeq
+		g.v7Lead = 0
 
-		case MillisecondPrecision:
-			if nsec/1000000 <= g.v7LastSubsec/1000000 {
-				if g.v7ClockSequence >= maxSeq12 {
-					return 0, 0, 0, errors.New("generating millisecond precision UUIDv7s too fast: internal clock sequence would roll over")
-				}
+	case unix < g.v7LastTime || (unix == g.v7LastTime && nsec/div <= g.v7LastSubsec/div):
+		if g.v7ClockSequence >= maxSeq {
+			return 0, 0, 0, fmt.Errorf("generating %s precision UUIDv7s too fast: internal clock sequence would roll over", p)
+		}
 
-				g.v7ClockSequence++
-			} else {
-				g.v7ClockSequence = 0
-			}
+		g.v7ClockSequence++
+		unix, nsec = g.v7LastTime, g.v7LastSubsec
 
-		default:
-			panic(fmt.Sprintf("unknown precision value %d", p))
-		}
+	default:
+		g.v7ClockSequence = 0
+		g.v7Lead = 0
 	}
 
 	g.v7LastTime = unix
@@ -522,11 +1689,17 @@ func (g *Gen) getV7ClockSequence(p Precision) (epoch uint64, nano uint64, seq ui
 func (g *Gen) getHardwareAddr() ([]byte, error) {
 	var err error
 	g.hardwareAddrOnce.Do(func() {
-		var hwAddr net.HardwareAddr
-		if hwAddr, err = g.hwAddrFunc(); err == nil {
-			copy(g.hardwareAddr[:], hwAddr)
+		if g.nodeID != nil {
+			g.hardwareAddr = *g.nodeID
 			return
 		}
+		if !g.RandomNodePreferred && !DefaultRandomNodePreferred {
+			var hwAddr net.HardwareAddr
+			if hwAddr, err = g.hwAddrFunc(); err == nil {
+				copy(g.hardwareAddr[:], hwAddr)
+				return
+			}
+		}
 
 		// Initialize hardwareAddr randomly in case
 		// of real network interfaces absence.
@@ -545,7 +1718,33 @@ func (g *Gen) getHardwareAddr() ([]byte, error) {
 // Returns the difference between UUID epoch (October 15, 1582)
 // and current time in 100-nanosecond intervals.
 func (g *Gen) getEpoch() uint64 {
-	return epochStart + uint64(g.epochFunc().UnixNano()/100)
+	return GregorianEpochOffset + uint64(g.epochFunc().UnixNano()/100)
+}
+
+// putV1Timestamp sets the time_low, time_mid, and time_hi fields of a V1 UUID
+// to the 60-bit timestamp ts.
+func putV1Timestamp(u *UUID, ts uint64) {
+	binary.BigEndian.PutUint32(u[0:], uint32(ts))
+	binary.BigEndian.PutUint16(u[4:], uint16(ts>>32))
+	binary.BigEndian.PutUint16(u[6:], uint16(ts>>48))
+}
+
+// putV6Timestamp sets the time_high, time_mid, and time_low fields of a V6 UUID
+// to the 60-bit timestamp ts.
+func putV6Timestamp(u *UUID, ts uint64) {
+	binary.BigEndian.PutUint32(u[0:], uint32(ts>>28))   // set time_high
+	binary.BigEndian.PutUint16(u[4:], uint16(ts>>12))   // set time_mid
+	binary.BigEndian.PutUint16(u[6:], uint16(ts&0xfff)) // set time_low (minus four version bits)
+}
+
+// putV7Milli sets the unixts, msec, and seq fields of a millisecond precision
+// V7 UUID.
+func putV7Milli(u *UUID, sec, msec uint64, seq uint16) {
+	d := (sec << 28)            // set unixts field
+	d |= ((msec & 0xfff) << 16) // set msec field
+	d |= (uint64(seq) & 0xfff)  // set seq field
+
+	binary.BigEndian.PutUint64(u[:], d)
 }
 
 // Returns the UUID based on the hashing of the namespace UUID and name.
@@ -558,6 +1757,23 @@ func newFromHash(h hash.Hash, ns UUID, name string) UUID {
 	return u
 }
 
+// nameBufSize is the size of the stack buffer used by NewV3Bytes and
+// NewV5Bytes to hash the namespace and name without allocating.
+const nameBufSize = 256
+
+// appendNamespaceName returns the namespace followed by the name, for hashing
+// by NewV3Bytes and NewV5Bytes. The result is stored in buf if it fits.
+func appendNamespaceName(buf *[nameBufSize]byte, ns UUID, name []byte) []byte {
+	var b []byte
+	if Size+len(name) <= len(buf) {
+		b = buf[:0]
+	} else {
+		b = make([]byte, 0, Size+len(name))
+	}
+	b = append(b, ns[:]...)
+	return append(b, name...)
+}
+
 // Returns the hardware address.
 func defaultHWAddrFunc() (net.HardwareAddr, error) {
 	ifaces, err := net.Interfaces()
@@ -23,11 +23,17 @@ package uuid
 
 import (
 	"bytes"
+	"crypto"
 	"crypto/rand"
+	"crypto/sha256"
 	"encoding/binary"
 	"fmt"
+	"hash"
+	"hash/fnv"
+	"io"
 	"net"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 )
@@ -39,6 +45,11 @@ func TestGenerator(t *testing.T) {
 	t.Run("NewV5", testNewV5)
 	t.Run("NewV6", testNewV6)
 	t.Run("NewV7", testNewV7)
+	t.Run("NewVxAt", testNewVxAt)
+	t.Run("NewV8", testNewV8)
+	t.Run("NewV8Epoch", testNewV8Epoch)
+	t.Run("NewV8Name", testNewV8Name)
+	t.Run("NewV8Hash", testNewV8Hash)
 }
 
 func testNewV1(t *testing.T) {
@@ -48,6 +59,9 @@ func testNewV1(t *testing.T) {
 	t.Run("FaultyRand", testNewV1FaultyRand)
 	t.Run("MissingNetwork", testNewV1MissingNetwork)
 	t.Run("MissingNetworkFaultyRand", testNewV1MissingNetworkFaultyRand)
+	t.Run("RandomNodePreferred", testNewV1RandomNodePreferred)
+	t.Run("StableStorage", testNewV1StableStorage)
+	t.Run("NodeID", testNewV1NodeID)
 }
 
 func TestNewGenWithHWAF(t *testing.T) {
@@ -79,6 +93,61 @@ func TestNewGenWithHWAF(t *testing.T) {
 	}
 }
 
+func TestNewGenWithOptions(t *testing.T) {
+	addr := net.HardwareAddr{0, 1, 2, 3, 4, 42}
+	now := time.Date(2021, 11, 26, 12, 34, 56, 789000000, time.UTC)
+
+	newGen := func() *Gen {
+		return NewGenWithOptions(
+			WithHWAddrFunc(func() (net.HardwareAddr, error) { return addr, nil }),
+			WithEpochFunc(func() time.Time { return now }),
+			WithRandomReader(zeroReader{}),
+		)
+	}
+
+	g1, g2 := newGen(), newGen()
+	gens := []func(g *Gen) (UUID, error){
+		(*Gen).NewV1,
+		(*Gen).NewV4,
+		(*Gen).NewV6,
+		func(g *Gen) (UUID, error) { return g.NewV7(MillisecondPrecision) },
+	}
+	for _, fn := range gens {
+		u1, err := fn(g1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		u2, err := fn(g2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if u1 != u2 {
+			t.Errorf("generators with equal options returned %v and %v, want equal", u1, u2)
+		}
+	}
+
+	u := Must(g1.NewV1())
+	if !bytes.Equal(u[10:], addr) {
+		t.Errorf("node = %v, want %v", net.HardwareAddr(u[10:]), addr)
+	}
+	if ts, err := u.Time(); err != nil || !ts.Equal(now) {
+		t.Errorf("%v.Time() = %v, %v, want %v", u, ts, err, now)
+	}
+	if u := Must(g1.NewV4()); u != Must(FromString("00000000-0000-4000-8000-000000000000")) {
+		t.Errorf("NewV4() with a zero reader = %v", u)
+	}
+
+	// nil options and no options use the defaults
+	for _, g := range []*Gen{NewGenWithOptions(), NewGenWithOptions(WithHWAddrFunc(nil), WithEpochFunc(nil), WithRandomReader(nil))} {
+		if g.rand != rand.Reader || g.epochFunc == nil || g.hwAddrFunc == nil {
+			t.Errorf("NewGenWithOptions() did not set defaults: %+v", g)
+		}
+		if _, err := g.NewV4(); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
 func testNewV1Basic(t *testing.T) {
 	u, err := NewV1()
 	if err != nil {
@@ -174,10 +243,173 @@ func testNewV1MissingNetworkFaultyRand(t *testing.T) {
 	}
 }
 
+func testNewV1RandomNodePreferred(t *testing.T) {
+	addr := []byte{0, 1, 2, 3, 4, 42}
+	hwaf := func() (net.HardwareAddr, error) {
+		t.Error("HWAddrFunc called with RandomNodePreferred set")
+		return addr, nil
+	}
+	g1 := NewGenWithHWAF(hwaf)
+	g1.RandomNodePreferred = true
+	g2 := NewGenWithOptions(WithHWAddrFunc(hwaf), WithRandomNodeID())
+
+	for _, g := range []*Gen{g1, g2} {
+		u1, err := g.NewV1()
+		if err != nil {
+			t.Fatal(err)
+		}
+		u2, err := g.NewV1()
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, u := range []UUID{u1, u2} {
+			node := u[10:]
+			if bytes.Equal(node, addr) {
+				t.Errorf("node = %v, want random node", node)
+			}
+			if node[0]&0x01 == 0 {
+				t.Errorf("node = %v, multicast bit not set", node)
+			}
+		}
+		if !bytes.Equal(u1[10:], u2[10:]) {
+			t.Errorf("node changed across calls: %v and %v", u1[10:], u2[10:])
+		}
+	}
+
+	// the package default applies to generators without the field set
+	DefaultRandomNodePreferred = true
+	defer func() { DefaultRandomNodePreferred = false }()
+	u, err := NewGenWithHWAF(hwaf).NewV1()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if node := u[10:]; bytes.Equal(node, addr) || node[0]&0x01 == 0 {
+		t.Errorf("node = %v with DefaultRandomNodePreferred, want random multicast node", node)
+	}
+}
+
+func testNewV1StableStorage(t *testing.T) {
+	var stored ClockState
+	storage := func(update func(ClockState) ClockState) error {
+		stored = update(stored)
+		return nil
+	}
+	addr := net.HardwareAddr{0, 1, 2, 3, 4, 42}
+	now := time.Date(2021, 11, 26, 12, 34, 56, 0, time.UTC)
+	newGen := func() *Gen {
+		return NewGenWithOptions(
+			WithHWAddrFunc(func() (net.HardwareAddr, error) { return addr, nil }),
+			WithEpochFunc(func() time.Time { return now }),
+			WithStableStorage(storage),
+		)
+	}
+
+	g := newGen()
+	u1 := Must(g.NewV1())
+	ts, _ := TimestampFromV1(u1)
+	if stored.LastTime != uint64(ts) {
+		t.Errorf("stored LastTime = %d, want %d", stored.LastTime, ts)
+	}
+	if seq := binary.BigEndian.Uint16(u1[8:]) & 0x3fff; stored.ClockSequence&0x3fff != seq {
+		t.Errorf("stored ClockSequence = %#x, want %#x", stored.ClockSequence, seq)
+	}
+
+	// a "restarted" process with its clock rolled back continues the stored
+	// clock sequence instead of picking a new random one
+	seen := map[UUID]bool{u1: true}
+	want := stored.ClockSequence
+	for i, fn := range []func(*Gen) (UUID, error){(*Gen).NewV1, (*Gen).NewV6} {
+		now = now.Add(-time.Second)
+		g = newGen()
+		u, err := fn(g)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want++
+		if stored.ClockSequence != want {
+			t.Errorf("#%d: stored ClockSequence = %#x, want %#x", i, stored.ClockSequence, want)
+		}
+		if seq := binary.BigEndian.Uint16(u[8:]) & 0x3fff; seq != want&0x3fff {
+			t.Errorf("#%d: %v has clock sequence %#x, want %#x", i, u, seq, want&0x3fff)
+		}
+		if seen[u] {
+			t.Errorf("#%d: duplicate UUID %v after restart", i, u)
+		}
+		seen[u] = true
+	}
+
+	// the clock sequence is reused once the clock moves past the stored time
+	now = now.Add(time.Hour)
+	if _, err := g.NewV1(); err != nil {
+		t.Fatal(err)
+	}
+	if stored.ClockSequence != want {
+		t.Errorf("stored ClockSequence = %#x, want %#x", stored.ClockSequence, want)
+	}
+
+	g = NewGenWithOptions(WithStableStorage(func(func(ClockState) ClockState) error {
+		return fmt.Errorf("storage is faulty")
+	}))
+	_, err := g.NewV1()
+	testErrCheck(t, "NewV1()", "storage is faulty", err)
+	_, err = g.NewV6()
+	testErrCheck(t, "NewV6()", "storage is faulty", err)
+
+	g = NewGenWithOptions(WithStableStorage(func(func(ClockState) ClockState) error { return nil }))
+	_, err = g.NewV1()
+	testErrCheck(t, "NewV1()", "did not update", err)
+}
+
+func testNewV1NodeID(t *testing.T) {
+	node := [6]byte{0x02, 0x42, 0xac, 0x11, 0x00, 0x02}
+	g := NewGenWithOptions(
+		WithHWAddrFunc(func() (net.HardwareAddr, error) {
+			t.Error("HWAddrFunc called with WithNodeID")
+			return net.HardwareAddr{0, 1, 2, 3, 4, 42}, nil
+		}),
+		WithNodeID(node),
+	)
+	g.RandomNodePreferred = true
+
+	for _, fn := range []func() (UUID, error){g.NewV1, g.NewV6, g.NewV1, g.NewV6} {
+		u, err := fn()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(u[10:], node[:]) {
+			t.Errorf("V%d node = %v, want %v", u.Version(), net.HardwareAddr(u[10:]), net.HardwareAddr(node[:]))
+		}
+	}
+
+	// the node does not depend on the random source
+	g = NewGenWithOptions(WithNodeID(node), WithRandomReader(&faultyReader{readToFail: 1}))
+	if _, err := g.NewV1(); err != nil {
+		t.Fatal(err)
+	}
+	if u, err := g.NewV6(); err != nil || !bytes.Equal(u[10:], node[:]) {
+		t.Errorf("NewV6() = %v, %v, want node %v", u, err, net.HardwareAddr(node[:]))
+	}
+}
+
 func testNewV3(t *testing.T) {
 	t.Run("Basic", testNewV3Basic)
 	t.Run("EqualNames", testNewV3EqualNames)
 	t.Run("DifferentNamespaces", testNewV3DifferentNamespaces)
+	t.Run("Bytes", testNewV3Bytes)
+}
+
+func testNewV3Bytes(t *testing.T) {
+	long := strings.Repeat("x", 1000)
+	for _, name := range []string{"", "www.example.com", strings.Repeat("x", nameBufSize-Size), long} {
+		if got, want := NewV3Bytes(NamespaceDNS, []byte(name)), NewV3(NamespaceDNS, name); got != want {
+			t.Errorf("NewV3Bytes(%v, %q) = %v, want %v", NamespaceDNS, name, got, want)
+		}
+	}
+
+	name := []byte("www.example.com")
+	if n := testing.AllocsPerRun(100, func() { NewV3Bytes(NamespaceDNS, name) }); n != 0 {
+		t.Errorf("NewV3Bytes() made %v allocations, want 0", n)
+	}
 }
 
 func testNewV3Basic(t *testing.T) {
@@ -222,6 +454,11 @@ func testNewV4(t *testing.T) {
 	t.Run("DifferentAcrossCalls", testNewV4DifferentAcrossCalls)
 	t.Run("FaultyRand", testNewV4FaultyRand)
 	t.Run("ShortRandomRead", testNewV4ShortRandomRead)
+	t.Run("Concurrent", testNewV4Concurrent)
+	t.Run("GenerateN", testGenerateV4N)
+	t.Run("Batch", testNewV4Batch)
+	t.Run("FromReader", testNewV4FromReader)
+	t.Run("RandPool", testNewV4RandPool)
 }
 
 func testNewV4Basic(t *testing.T) {
@@ -251,6 +488,197 @@ func testNewV4DifferentAcrossCalls(t *testing.T) {
 	}
 }
 
+func testNewV4Concurrent(t *testing.T) {
+	const goroutines = 8
+	n := 10000
+	if testing.Short() {
+		n = 1000
+	}
+
+	results := make([][]UUID, goroutines)
+	var wg sync.WaitGroup
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			us := make([]UUID, n)
+			for j := range us {
+				us[j] = Must(NewV4())
+			}
+			results[i] = us
+		}(i)
+	}
+	wg.Wait()
+
+	seen := make(map[UUID]bool, goroutines*n)
+	for _, us := range results {
+		for _, u := range us {
+			if u.Version() != V4 || u.Variant() != VariantRFC4122 {
+				t.Fatalf("%v has version %d and variant %d", u, u.Version(), u.Variant())
+			}
+			if seen[u] {
+				t.Fatalf("duplicate UUID: %v", u)
+			}
+			seen[u] = true
+		}
+	}
+}
+
+func testGenerateV4N(t *testing.T) {
+	const n = 1000
+	var buf bytes.Buffer
+	if err := GenerateV4N(&buf, n); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(buf.String(), "\n")
+	if len(lines) != n+1 || lines[n] != "" {
+		t.Fatalf("GenerateV4N(%d) wrote %d lines, want %d newline terminated lines", n, len(lines)-1, n)
+	}
+	seen := make(map[UUID]bool, n)
+	for _, line := range lines[:n] {
+		if !IsCanonical(line) {
+			t.Fatalf("GenerateV4N() wrote non-canonical line %q", line)
+		}
+		u := Must(FromString(line))
+		if u.Version() != V4 || u.Variant() != VariantRFC4122 {
+			t.Fatalf("%v has version %d and variant %d", u, u.Version(), u.Variant())
+		}
+		if seen[u] {
+			t.Fatalf("GenerateV4N() wrote duplicate UUID %v", u)
+		}
+		seen[u] = true
+	}
+
+	buf.Reset()
+	if err := GenerateV4N(&buf, 0); err != nil || buf.Len() != 0 {
+		t.Errorf("GenerateV4N(0) = %v, wrote %q", err, buf.String())
+	}
+	if err := GenerateV4N(&buf, -1); err == nil {
+		t.Error("GenerateV4N(-1): want error")
+	}
+	if err := GenerateV4N(&failingWriter{}, n); err == nil {
+		t.Error("GenerateV4N() with a failing writer: want error")
+	}
+}
+
+// countingReader counts the calls to Read of the wrapped reader.
+type countingReader struct {
+	io.Reader
+	callsNum int
+}
+
+func (r *countingReader) Read(dest []byte) (int, error) {
+	r.callsNum++
+	return r.Reader.Read(dest)
+}
+
+func testNewV4Batch(t *testing.T) {
+	const n = 1000
+	us, err := NewV4Batch(n)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(us) != n {
+		t.Fatalf("NewV4Batch(%d) returned %d UUIDs", n, len(us))
+	}
+	seen := make(map[UUID]bool, n)
+	for _, u := range us {
+		if u.Version() != V4 || u.Variant() != VariantRFC4122 {
+			t.Fatalf("%v has version %d and variant %d", u, u.Version(), u.Variant())
+		}
+		if seen[u] {
+			t.Fatalf("NewV4Batch() returned duplicate UUID %v", u)
+		}
+		seen[u] = true
+	}
+
+	r := &countingReader{Reader: rand.Reader}
+	g := NewGenWithOptions(WithRandomReader(r))
+	dst := make([]UUID, n)
+	if err := g.FillV4(dst); err != nil {
+		t.Fatal(err)
+	}
+	if r.callsNum != 1 {
+		t.Errorf("FillV4() made %d reads, want 1", r.callsNum)
+	}
+	for _, u := range dst {
+		if u.Version() != V4 || u.Variant() != VariantRFC4122 {
+			t.Fatalf("%v has version %d and variant %d", u, u.Version(), u.Variant())
+		}
+	}
+
+	if us, err := NewV4Batch(0); err != nil || len(us) != 0 {
+		t.Errorf("NewV4Batch(0) = %v, %v, want no UUIDs", us, err)
+	}
+	if err := FillV4(nil); err != nil {
+		t.Errorf("FillV4(nil) = %v", err)
+	}
+	if _, err := NewV4Batch(-1); err == nil {
+		t.Error("NewV4Batch(-1): want error")
+	}
+	g = NewGenWithOptions(WithRandomReader(&faultyReader{}))
+	if _, err := g.NewV4Batch(n); err == nil {
+		t.Error("NewV4Batch() with a faulty reader: want error")
+	}
+}
+
+func testNewV4FromReader(t *testing.T) {
+	r := bytes.NewReader(codecTestData)
+	u, err := NewV4FromReader(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := Must(FromString("6ba7b810-9dad-41d1-80b4-00c04fd430c8")); u != want {
+		t.Errorf("NewV4FromReader() = %v, want %v", u, want)
+	}
+	if r.Len() != 0 {
+		t.Errorf("NewV4FromReader() left %d unread bytes, want 0", r.Len())
+	}
+
+	if _, err := NewV4FromReader(bytes.NewReader(codecTestData[:15])); err == nil {
+		t.Error("NewV4FromReader() with a short reader: want error")
+	}
+	if _, err := NewV4FromReader(&faultyReader{}); err == nil {
+		t.Error("NewV4FromReader() with a faulty reader: want error")
+	}
+}
+
+func testNewV4RandPool(t *testing.T) {
+	if randPoolEnabled() {
+		t.Fatal("randPoolEnabled() = true by default, want false")
+	}
+	defer DisableRandPool()
+
+	for _, enabled := range []bool{true, false} {
+		if enabled {
+			EnableRandPool()
+		} else {
+			DisableRandPool()
+		}
+		if got := randPoolEnabled(); got != enabled {
+			t.Fatalf("randPoolEnabled() = %t, want %t", got, enabled)
+		}
+		seen := make(map[UUID]bool)
+		for i := 0; i < 2*entropyBufSize/Size; i++ {
+			u := Must(NewV4())
+			if u.Version() != V4 || u.Variant() != VariantRFC4122 {
+				t.Fatalf("%v has version %d and variant %d", u, u.Version(), u.Variant())
+			}
+			if seen[u] {
+				t.Fatalf("duplicate UUID with pool enabled %t: %v", enabled, u)
+			}
+			seen[u] = true
+		}
+	}
+}
+
+// failingWriter is an io.Writer that always fails.
+type failingWriter struct{}
+
+func (*failingWriter) Write([]byte) (int, error) {
+	return 0, fmt.Errorf("io: writer is faulty")
+}
+
 func testNewV4FaultyRand(t *testing.T) {
 	g := &Gen{
 		epochFunc:  time.Now,
@@ -283,6 +711,12 @@ func testNewV5(t *testing.T) {
 	t.Run("Basic", testNewV5Basic)
 	t.Run("EqualNames", testNewV5EqualNames)
 	t.Run("DifferentNamespaces", testNewV5DifferentNamespaces)
+	t.Run("FromURL", testNewV5FromURL)
+	t.Run("Namespace", testNewNamespace)
+	t.Run("Builder", testNewV5Builder)
+	t.Run("TenantID", testTenantID)
+	t.Run("Verify", testVerifyV5)
+	t.Run("Bytes", testNewV5Bytes)
 }
 
 func testNewV5Basic(t *testing.T) {
@@ -301,6 +735,20 @@ func testNewV5Basic(t *testing.T) {
 	}
 }
 
+func testNewV5Bytes(t *testing.T) {
+	long := strings.Repeat("x", 1000)
+	for _, name := range []string{"", "www.example.com", strings.Repeat("x", nameBufSize-Size), long} {
+		if got, want := NewV5Bytes(NamespaceDNS, []byte(name)), NewV5(NamespaceDNS, name); got != want {
+			t.Errorf("NewV5Bytes(%v, %q) = %v, want %v", NamespaceDNS, name, got, want)
+		}
+	}
+
+	name := []byte("www.example.com")
+	if n := testing.AllocsPerRun(100, func() { NewV5Bytes(NamespaceDNS, name) }); n != 0 {
+		t.Errorf("NewV5Bytes() made %v allocations, want 0", n)
+	}
+}
+
 func testNewV5EqualNames(t *testing.T) {
 	ns := NamespaceDNS
 	name := "example.com"
@@ -322,99 +770,444 @@ func testNewV5DifferentNamespaces(t *testing.T) {
 	}
 }
 
-func testNewV6(t *testing.T) {
-	t.Run("Basic", testNewV6Basic)
-	t.Run("DifferentAcrossCalls", testNewV6DifferentAcrossCalls)
-	t.Run("StaleEpoch", testNewV6StaleEpoch)
-	t.Run("FaultyRand", testNewV6FaultyRand)
-	t.Run("ShortRandomRead", testNewV6ShortRandomRead)
-	t.Run("KSortable", testNewV6KSortable)
+func testNewV5FromURL(t *testing.T) {
+	equivalent := [][]string{
+		{
+			"http://example.com/a?a=2&b=1",
+			"http://Example.com:80/a?b=1&a=2",
+			"HTTP://EXAMPLE.COM/a?b=1&a=2",
+		},
+		{
+			"https://example.com/",
+			"https://example.com:443/",
+		},
+		{
+			"http://[::1]/x",
+			"http://[::1]:80/x",
+		},
+	}
+	for _, urls := range equivalent {
+		want := Must(NewV5FromURL(urls[0]))
+		if got := NewV5(NamespaceURL, urls[0]); got != want {
+			t.Errorf("NewV5FromURL(%q) = %v, want %v", urls[0], want, got)
+		}
+		for _, s := range urls[1:] {
+			got, err := NewV5FromURL(s)
+			if err != nil {
+				t.Fatalf("NewV5FromURL(%q): %v", s, err)
+			}
+			if got != want {
+				t.Errorf("NewV5FromURL(%q) = %v, want %v", s, got, want)
+			}
+		}
+	}
+
+	different := []string{
+		"http://example.com/a",
+		"http://example.com/A",
+		"http://example.com:8080/a",
+		"https://example.com/a",
+		"http://example.com/a?a=1",
+		"http://example.com/a?a=1&a=2",
+		"http://example.com/a?a=2&a=1",
+	}
+	seen := make(map[UUID]string)
+	for _, s := range different {
+		u := Must(NewV5FromURL(s))
+		if prev, ok := seen[u]; ok {
+			t.Errorf("NewV5FromURL(%q) == NewV5FromURL(%q) (%v)", s, prev, u)
+		}
+		seen[u] = s
+	}
+
+	if u, err := NewV5FromURL("http://[::1"); err == nil {
+		t.Errorf("NewV5FromURL() with invalid URL = %v, want error", u)
+	}
 }
 
-func testNewV6Basic(t *testing.T) {
-	u, err := NewV6()
-	if err != nil {
-		t.Fatal(err)
+func testNewNamespace(t *testing.T) {
+	ns1 := NewNamespace("com.example.users")
+	if got := NewNamespace("com.example.users"); got != ns1 {
+		t.Errorf("NewNamespace() generated %v and %v across two calls", ns1, got)
 	}
-	if got, want := u.Version(), V6; got != want {
-		t.Errorf("generated UUID with version %d, want %d", got, want)
+	if want := NewV5(NamespaceOID, "com.example.users"); ns1 != want {
+		t.Errorf("NewNamespace(%q) = %v, want %v", "com.example.users", ns1, want)
 	}
-	if got, want := u.Variant(), VariantRFC4122; got != want {
-		t.Errorf("generated UUID with variant %d, want %d", got, want)
+	ns2 := NewNamespace("com.example.groups")
+	if ns1 == ns2 {
+		t.Errorf("NewNamespace() returned %v for different names", ns1)
+	}
+	if NewV5(ns1, "alice") == NewV5(ns2, "alice") {
+		t.Errorf("NewV5() returned the same UUID for different namespaces")
 	}
 }
 
-func testNewV6DifferentAcrossCalls(t *testing.T) {
-	u1, err := NewV6()
-	if err != nil {
-		t.Fatal(err)
+func testTenantID(t *testing.T) {
+	tenant1 := NewNamespace("tenant-1")
+	tenant2 := NewNamespace("tenant-2")
+
+	u := TenantID(tenant1, "invoice/42")
+	if got := TenantID(tenant1, "invoice/42"); got != u {
+		t.Errorf("TenantID() generated %v and %v across two calls", u, got)
 	}
-	u2, err := NewV6()
-	if err != nil {
-		t.Fatal(err)
+	if want := NewV5(tenant1, "invoice/42"); u != want {
+		t.Errorf("TenantID(%v, %q) = %v, want %v", tenant1, "invoice/42", u, want)
 	}
-	if u1 == u2 {
-		t.Errorf("generated identical UUIDs across calls: %v", u1)
+	if got := TenantID(tenant2, "invoice/42"); got == u {
+		t.Errorf("TenantID() returned %v for different tenants", u)
+	}
+	if got := TenantID(tenant1, "invoice/43"); got == u {
+		t.Errorf("TenantID() returned %v for different keys", u)
 	}
 }
 
-func testNewV6StaleEpoch(t *testing.T) {
-	g := &Gen{
-		epochFunc: func() time.Time {
-			return time.Unix(0, 0)
-		},
-		hwAddrFunc: defaultHWAddrFunc,
-		rand:       rand.Reader,
+func testVerifyV5(t *testing.T) {
+	// test vector from RFC 9562 appendix A.4
+	u := FromStringOrNil("2ed6657d-e927-568b-95e1-2665a8aea6a2")
+	if !VerifyV5(u, NamespaceDNS, "www.example.com") {
+		t.Errorf("VerifyV5(%v, %v, %q) = false, want true", u, NamespaceDNS, "www.example.com")
 	}
-	u1, err := g.NewV6()
-	if err != nil {
-		t.Fatal(err)
+	if VerifyV5(u, NamespaceURL, "www.example.com") {
+		t.Errorf("VerifyV5(%v, %v, %q) = true, want false", u, NamespaceURL, "www.example.com")
 	}
-	u2, err := g.NewV6()
-	if err != nil {
-		t.Fatal(err)
+	if VerifyV5(u, NamespaceDNS, "example.com") {
+		t.Errorf("VerifyV5(%v, %v, %q) = true, want false", u, NamespaceDNS, "example.com")
 	}
-	if u1 == u2 {
-		t.Errorf("generated identical UUIDs across calls: %v", u1)
+	if v3 := NewV3(NamespaceDNS, "www.example.com"); VerifyV5(v3, NamespaceDNS, "www.example.com") {
+		t.Errorf("VerifyV5(%v, %v, %q) = true, want false", v3, NamespaceDNS, "www.example.com")
 	}
 }
 
-func testNewV6FaultyRand(t *testing.T) {
-	t.Run("randomData", func(t *testing.T) {
-		g := &Gen{
-			epochFunc:  time.Now,
-			hwAddrFunc: defaultHWAddrFunc,
-			rand: &faultyReader{
-				readToFail: 0, // fail immediately
-			},
+func testNewVxAt(t *testing.T) {
+	at := time.Date(2019, 6, 7, 8, 9, 10, 123456700, time.UTC)
+	node := [6]byte{0x02, 0x42, 0xac, 0x11, 0x00, 0x02}
+	g := NewGenWithOptions(WithNodeID(node), WithEpochFunc(func() time.Time {
+		t.Error("clock read by a NewVxAt method")
+		return time.Now()
+	}))
+
+	newV7At := func(p Precision) func(time.Time) (UUID, error) {
+		return func(t time.Time) (UUID, error) { return g.NewV7At(t, p) }
+	}
+	tests := []struct {
+		version byte
+		p       Precision // V7 only
+		fn      func(time.Time) (UUID, error)
+		want    time.Time
+	}{
+		{V1, 0, g.NewV1At, at},
+		{V6, 0, g.NewV6At, at},
+		{V7, MillisecondPrecision, newV7At(MillisecondPrecision), at.Truncate(time.Millisecond)},
+		{V7, MicrosecondPrecision, newV7At(MicrosecondPrecision), at.Truncate(time.Microsecond)},
+	}
+	for _, tt := range tests {
+		u1, err := tt.fn(at)
+		if err != nil {
+			t.Fatal(err)
 		}
-		u, err := g.NewV6()
-		if err == nil {
-			t.Fatalf("got %v, want error", u)
+		u2, err := tt.fn(at)
+		if err != nil {
+			t.Fatal(err)
 		}
-		if u != Nil {
-			t.Fatalf("got %v on error, want Nil", u)
+		if u1.Version() != tt.version || u1.Variant() != VariantRFC4122 {
+			t.Errorf("%v has version %d and variant %d, want version %d", u1, u1.Version(), u1.Variant(), tt.version)
 		}
-	})
-
-	t.Run("clockSequence", func(t *testing.T) {
-		g := &Gen{
-			epochFunc:  time.Now,
-			hwAddrFunc: defaultHWAddrFunc,
-			rand: &faultyReader{
-				readToFail: 1, // fail immediately
-			},
+		if u1 == u2 {
+			t.Errorf("V%d: generated %v twice for the same time", tt.version, u1)
 		}
-		u, err := g.NewV6()
-		if err == nil {
-			t.Fatalf("got %v, want error", u)
+		var ts time.Time
+		if tt.version == V7 {
+			ts, err = TimeFromV7(u1, tt.p)
+		} else {
+			ts, err = u1.Time()
 		}
-		if u != Nil {
-			t.Fatalf("got %v on error, want Nil", u)
+		if err != nil || !ts.Equal(tt.want) {
+			t.Errorf("V%d: %v has time %v, %v, want %v", tt.version, u1, ts, err, tt.want)
 		}
-	})
-}
-
+		if tt.version != V7 && !bytes.Equal(u1[10:], node[:]) {
+			t.Errorf("V%d: node = %v, want %v", tt.version, net.HardwareAddr(u1[10:]), net.HardwareAddr(node[:]))
+		}
+	}
+
+	// the generator's own clock state is not advanced
+	if g.lastTime != 0 || g.v7LastTime != 0 {
+		t.Errorf("NewVxAt methods advanced the clock state to %d and %d", g.lastTime, g.v7LastTime)
+	}
+
+	// the package-level functions use the default generator
+	if u, err := NewV1At(at); err != nil || u.Version() != V1 {
+		t.Errorf("NewV1At() = %v, %v", u, err)
+	}
+	if u, err := NewV6At(at); err != nil || u.Version() != V6 {
+		t.Errorf("NewV6At() = %v, %v", u, err)
+	}
+	if u, err := NewV7At(at, MillisecondPrecision); err != nil || u.Version() != V7 {
+		t.Errorf("NewV7At() = %v, %v", u, err)
+	}
+
+	for _, bad := range []time.Time{time.Date(1500, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(3000, 1, 1, 0, 0, 0, 0, time.UTC)} {
+		if u, err := g.NewV1At(bad); err == nil {
+			t.Errorf("NewV1At(%v) = %v, want error", bad, u)
+		}
+		if u, err := g.NewV6At(bad); err == nil {
+			t.Errorf("NewV6At(%v) = %v, want error", bad, u)
+		}
+	}
+	if u, err := g.NewV7At(time.Unix(-1, 0), MillisecondPrecision); err == nil {
+		t.Errorf("NewV7At(before 1970) = %v, want error", u)
+	}
+}
+
+func testNewV8(t *testing.T) {
+	// test vector from RFC 9562 appendix B.1, with the version and variant
+	// bits cleared
+	custom := [16]byte{
+		0x24, 0x89, 0xe9, 0xad, 0x2e, 0xe2, 0x0e, 0x00,
+		0x0e, 0xc9, 0x32, 0xd5, 0xf6, 0x91, 0x81, 0xc0,
+	}
+	want := Must(FromString("2489e9ad-2ee2-8e00-8ec9-32d5f69181c0"))
+	if u := NewV8(custom); u != want {
+		t.Errorf("NewV8(%x) = %v, want %v", custom, u, want)
+	}
+
+	tests := []struct {
+		custom [16]byte
+		want   UUID
+	}{
+		{custom: [16]byte{}, want: Must(FromString("00000000-0000-8000-8000-000000000000"))},
+		{custom: Max, want: Must(FromString("ffffffff-ffff-8fff-bfff-ffffffffffff"))},
+		{custom: codecTestUUID, want: Must(FromString("6ba7b810-9dad-81d1-80b4-00c04fd430c8"))},
+	}
+	for _, tt := range tests {
+		u := NewV8(tt.custom)
+		if u != tt.want {
+			t.Errorf("NewV8(%x) = %v, want %v", tt.custom, u, tt.want)
+		}
+		if v := u.Version(); v != V8 {
+			t.Errorf("NewV8(%x) generated a version %d UUID, want %d", tt.custom, v, V8)
+		}
+		if v := u.Variant(); v != VariantRFC4122 {
+			t.Errorf("NewV8(%x) generated variant %d, want %d", tt.custom, v, VariantRFC4122)
+		}
+	}
+}
+
+func testNewV8Hash(t *testing.T) {
+	// test vector from RFC 9562 appendix B.2
+	name := []byte("www.example.com")
+	want := Must(FromString("5c146b14-3c52-8afd-938a-375d0df1fbf6"))
+	if u := NewV8Hash(NamespaceDNS, name, crypto.SHA256); u != want {
+		t.Errorf("NewV8Hash(%v, %q, crypto.SHA256) = %v, want %v", NamespaceDNS, name, u, want)
+	}
+	if got, want := NewV8Hash(NamespaceDNS, name, crypto.SHA256), NewV8Name(NamespaceDNS, name, sha256.New); got != want {
+		t.Errorf("NewV8Hash() = %v, NewV8Name() = %v, want equal", got, want)
+	}
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("NewV8Hash() with an unavailable hash did not panic")
+		}
+	}()
+	NewV8Hash(NamespaceDNS, name, crypto.Hash(0))
+}
+
+func testNewV8Epoch(t *testing.T) {
+	epoch := time.Date(2015, 3, 1, 0, 0, 0, 0, time.UTC)
+	now := epoch.Add(1234567890123 * time.Millisecond)
+	g := NewGenWithOptions(WithEpochFunc(func() time.Time { return now }), WithV8Epoch(epoch))
+
+	u, err := g.NewV8Epoch()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.Version() != V8 || u.Variant() != VariantRFC4122 {
+		t.Fatalf("%v has version %d and variant %d", u, u.Version(), u.Variant())
+	}
+	if got, want := binary.BigEndian.Uint64(u[:8])>>16, uint64(1234567890123); got != want {
+		t.Errorf("%v timestamp = %d, want %d", u, got, want)
+	}
+	if ts, err := g.TimeFromV8(u); err != nil || !ts.Equal(now) {
+		t.Errorf("TimeFromV8(%v) = %v, %v, want %v", u, ts, err, now)
+	}
+	if ts, err := TimeFromV8Epoch(u, epoch); err != nil || !ts.Equal(now) {
+		t.Errorf("TimeFromV8Epoch(%v) = %v, %v, want %v", u, ts, err, now)
+	}
+
+	// UUIDs sort by time
+	now = now.Add(time.Millisecond)
+	if next := Must(g.NewV8Epoch()); Compare(u, next) >= 0 {
+		t.Errorf("NewV8Epoch() = %v, not after %v", next, u)
+	}
+
+	// the range of the timestamp exceeds that of time.Duration
+	now = epoch.AddDate(5000, 0, 0).Add(500 * time.Millisecond)
+	u = Must(g.NewV8Epoch())
+	if ts, _ := g.TimeFromV8(u); !ts.Equal(now) {
+		t.Errorf("TimeFromV8(%v) = %v, want %v", u, ts, now)
+	}
+
+	// the Unix epoch is the default
+	now = time.Date(2021, 11, 26, 12, 34, 56, 789000000, time.UTC)
+	dg := NewGenWithOptions(WithEpochFunc(func() time.Time { return now }))
+	u = Must(dg.NewV8Epoch())
+	if got, want := binary.BigEndian.Uint64(u[:8])>>16, uint64(now.UnixNano()/1000000); got != want {
+		t.Errorf("%v timestamp = %d, want %d", u, got, want)
+	}
+
+	for _, bad := range []time.Time{epoch.Add(-time.Millisecond), epoch.AddDate(9000, 0, 0)} {
+		now = bad
+		if u, err := g.NewV8Epoch(); err == nil {
+			t.Errorf("NewV8Epoch() at %v = %v, want error", bad, u)
+		}
+	}
+	if _, err := g.TimeFromV8(Must(NewV4())); err == nil {
+		t.Error("TimeFromV8() of a V4 UUID: want error")
+	}
+}
+
+func testNewV8Name(t *testing.T) {
+	// test vector from RFC 9562 appendix B.2
+	want := Must(FromString("5c146b14-3c52-8afd-938a-375d0df1fbf6"))
+	name := []byte("www.example.com")
+
+	u := NewV8Name(NamespaceDNS, name, sha256.New)
+	if u != want {
+		t.Errorf("NewV8Name(%v, %q, sha256.New) = %v, want %v", NamespaceDNS, name, u, want)
+	}
+	if got := NewV8Name(NamespaceDNS, name, sha256.New); got != u {
+		t.Errorf("NewV8Name() generated %v and %v across two calls", u, got)
+	}
+	if got, want := u.Version(), V8; got != want {
+		t.Errorf("got version %d, want %d", got, want)
+	}
+	if got, want := u.Variant(), VariantRFC4122; got != want {
+		t.Errorf("got variant %d, want %d", got, want)
+	}
+	if got := NewV8Name(NamespaceURL, name, sha256.New); got == u {
+		t.Errorf("NewV8Name() returned %v for different namespaces", u)
+	}
+	if got := NewV8Name(NamespaceDNS, []byte("example.com"), sha256.New); got == u {
+		t.Errorf("NewV8Name() returned %v for different names", u)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("NewV8Name() with a 64-bit hash did not panic")
+		}
+	}()
+	NewV8Name(NamespaceDNS, name, func() hash.Hash { return fnv.New64() })
+}
+
+func testNewV5Builder(t *testing.T) {
+	name := "www.example.com"
+	b := NewV5Builder(NamespaceDNS, name)
+	for _, ns := range []UUID{NamespaceDNS, NamespaceURL, NamespaceOID, NamespaceX500} {
+		want := NewV5(ns, name)
+		if got := b.WithNamespace(ns).UUID(); got != want {
+			t.Errorf("NewV5Builder(%v, %q).WithNamespace(%v).UUID() = %v, want %v", NamespaceDNS, name, ns, got, want)
+		}
+	}
+	if got, want := b.UUID(), NewV5(NamespaceDNS, name); got != want {
+		t.Errorf("NewV5Builder(%v, %q).UUID() = %v, want %v", NamespaceDNS, name, got, want)
+	}
+	if got, want := b.WithName("example.org").UUID(), NewV5(NamespaceDNS, "example.org"); got != want {
+		t.Errorf("NewV5Builder(%v, %q).WithName(%q).UUID() = %v, want %v", NamespaceDNS, name, "example.org", got, want)
+	}
+}
+
+func testNewV6(t *testing.T) {
+	t.Run("Basic", testNewV6Basic)
+	t.Run("DifferentAcrossCalls", testNewV6DifferentAcrossCalls)
+	t.Run("StaleEpoch", testNewV6StaleEpoch)
+	t.Run("FaultyRand", testNewV6FaultyRand)
+	t.Run("ShortRandomRead", testNewV6ShortRandomRead)
+	t.Run("KSortable", testNewV6KSortable)
+}
+
+func testNewV6Basic(t *testing.T) {
+	u, err := NewV6()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := u.Version(), V6; got != want {
+		t.Errorf("generated UUID with version %d, want %d", got, want)
+	}
+	if got, want := u.Variant(), VariantRFC4122; got != want {
+		t.Errorf("generated UUID with variant %d, want %d", got, want)
+	}
+}
+
+func testNewV6DifferentAcrossCalls(t *testing.T) {
+	u1, err := NewV6()
+	if err != nil {
+		t.Fatal(err)
+	}
+	u2, err := NewV6()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u1 == u2 {
+		t.Errorf("generated identical UUIDs across calls: %v", u1)
+	}
+}
+
+func testNewV6StaleEpoch(t *testing.T) {
+	g := &Gen{
+		epochFunc: func() time.Time {
+			return time.Unix(0, 0)
+		},
+		hwAddrFunc: defaultHWAddrFunc,
+		rand:       rand.Reader,
+	}
+	u1, err := g.NewV6()
+	if err != nil {
+		t.Fatal(err)
+	}
+	u2, err := g.NewV6()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u1 == u2 {
+		t.Errorf("generated identical UUIDs across calls: %v", u1)
+	}
+}
+
+func testNewV6FaultyRand(t *testing.T) {
+	t.Run("randomData", func(t *testing.T) {
+		g := &Gen{
+			epochFunc:  time.Now,
+			hwAddrFunc: defaultHWAddrFunc,
+			rand: &faultyReader{
+				readToFail: 0, // fail immediately
+			},
+		}
+		u, err := g.NewV6()
+		if err == nil {
+			t.Fatalf("got %v, want error", u)
+		}
+		if u != Nil {
+			t.Fatalf("got %v on error, want Nil", u)
+		}
+	})
+
+	t.Run("clockSequence", func(t *testing.T) {
+		g := &Gen{
+			epochFunc:  time.Now,
+			hwAddrFunc: defaultHWAddrFunc,
+			rand: &faultyReader{
+				readToFail: 1, // fail immediately
+			},
+		}
+		u, err := g.NewV6()
+		if err == nil {
+			t.Fatalf("got %v, want error", u)
+		}
+		if u != Nil {
+			t.Fatalf("got %v on error, want Nil", u)
+		}
+	})
+}
+
 func testNewV6ShortRandomRead(t *testing.T) {
 	g := &Gen{
 		epochFunc: time.Now,
@@ -463,6 +1256,13 @@ func testNewV7(t *testing.T) {
 	}
 
 	t.Run("ClockSequence", testNewV7ClockSequence)
+	t.Run("Batch", testNewV7Batch)
+	t.Run("BatchAt", testNewV7BatchAt)
+	t.Run("MaxFutureSkew", testNewV7MaxFutureSkew)
+	t.Run("UniqueTail", testNewV7UniqueTail)
+	t.Run("MonotonicRandom", testNewV7MonotonicRandom)
+	t.Run("SubMillisecondFraction", testNewV7SubMillisecondFraction)
+	t.Run("GenerateOver", testGenerateV7Over)
 }
 
 func testNewV7InvalidPrecision(t *testing.T) {
@@ -644,6 +1444,24 @@ func makeTestNewV7ClockSequenceBehaviors(p Precision) func(t *testing.T) {
 			}
 		})
 
+		t.Run("ClockStepBack", func(t *testing.T) {
+			now := time.Date(2021, 11, 26, 12, 34, 56, 789000000, time.UTC)
+			g := NewGenWithOptions(WithEpochFunc(func() time.Time { return now }))
+			Must(g.NewV7(p))
+
+			// more calls than the sequence allows at one timestamp, with the
+			// clock stepped back and advancing by less than the precision
+			now = now.Add(-time.Hour)
+			for i := 0; i < 2*(maxSeq12+1); i++ {
+				if _, err := g.NewV7(p); err != nil {
+					t.Fatalf("g.NewV7() #%d after the clock stepped back: %v", i, err)
+				}
+				if i%2 == 1 {
+					now = now.Add(p.Duration())
+				}
+			}
+		})
+
 		t.Run("NominalTime", func(t *testing.T) {
 			g := NewGen()
 			g.v7ClockSequence = 100
@@ -744,6 +1562,518 @@ func testNewV7ClockSequence(t *testing.T) {
 	}
 }
 
+// repeatingReader fills every other Read with zeros, and the rest with random
+// data.
+type repeatingReader struct {
+	callsNum int
+}
+
+func (r *repeatingReader) Read(dest []byte) (int, error) {
+	r.callsNum++
+	if r.callsNum%2 == 1 {
+		for i := range dest {
+			dest[i] = 0
+		}
+		return len(dest), nil
+	}
+	return rand.Read(dest)
+}
+
+func testNewV7UniqueTail(t *testing.T) {
+	now := time.Date(2021, 11, 26, 12, 34, 56, 789000000, time.UTC)
+	newGen := func(r io.Reader) *Gen {
+		g := NewGen()
+		g.epochFunc = func() time.Time { return now }
+		g.rand = r
+		return g
+	}
+
+	for _, p := range []Precision{MillisecondPrecision, MicrosecondPrecision, NanosecondPrecision} {
+		n := maxSeq12 + 1
+		if p == NanosecondPrecision {
+			n = maxSeq8 + 1
+		}
+
+		g := newGen(&repeatingReader{})
+		g.UniqueTail = true
+		seen := make(map[UUID]bool, n)
+		tails := make(map[[8]byte]bool, n)
+		for i := 0; i < n; i++ {
+			u, err := g.NewV7(p)
+			if err != nil {
+				t.Fatalf("%s: NewV7() #%d: %v", p, i, err)
+			}
+			if u.Version() != V7 || u.Variant() != VariantRFC4122 {
+				t.Fatalf("%s: NewV7() = %v, want a V7 RFC-4122 UUID", p, u)
+			}
+			var tail [8]byte
+			copy(tail[:], u[8:])
+			if seen[u] || tails[tail] {
+				t.Fatalf("%s: NewV7() #%d returned duplicate %v", p, i, u)
+			}
+			seen[u] = true
+			tails[tail] = true
+		}
+	}
+
+	// without UniqueTail the repeated random data produces repeated tails
+	g := newGen(&repeatingReader{})
+	a := Must(g.NewV7(MillisecondPrecision))
+	g.rand = &repeatingReader{}
+	if b := Must(g.NewV7(MillisecondPrecision)); !bytes.Equal(a[8:], b[8:]) {
+		t.Errorf("NewV7() tails %x and %x differ, want equal", a[8:], b[8:])
+	}
+
+	// a random source that never changes cannot produce a unique tail
+	g = newGen(zeroReader{})
+	g.UniqueTail = true
+	if _, err := g.NewV7(MillisecondPrecision); err != nil {
+		t.Fatal(err)
+	}
+	u, err := g.NewV7(MillisecondPrecision)
+	testErrCheck(t, "NewV7()", "no unique V7 tail", err)
+	if u != Nil {
+		t.Errorf("NewV7() = %v on error, want Nil", u)
+	}
+
+	// the tracked tails are reset when the millisecond changes
+	now = now.Add(time.Millisecond)
+	if _, err := g.NewV7(MillisecondPrecision); err != nil {
+		t.Fatalf("NewV7() in a new millisecond: %v", err)
+	}
+
+	// MonotonicRandom UUIDs keep their order, the counter keeps them unique
+	g = newGen(zeroReader{})
+	g.UniqueTail = true
+	g.MonotonicRandom = true
+	prev := Nil
+	for i := 0; i < 100; i++ {
+		u, err := g.NewV7(MillisecondPrecision)
+		if err != nil {
+			t.Fatalf("NewV7() #%d with MonotonicRandom: %v", i, err)
+		}
+		if Compare(prev, u) >= 0 {
+			t.Fatalf("NewV7() #%d = %v, not after %v", i, u, prev)
+		}
+		prev = u
+	}
+}
+
+// onesReader fills every Read with 0xff bytes.
+type onesReader struct{}
+
+func (onesReader) Read(dest []byte) (int, error) {
+	for i := range dest {
+		dest[i] = 0xff
+	}
+	return len(dest), nil
+}
+
+func testNewV7MonotonicRandom(t *testing.T) {
+	now := time.Date(2021, 11, 26, 12, 34, 56, 789000000, time.UTC)
+	g := NewGenWithOptions(WithEpochFunc(func() time.Time { return now }))
+	g.MonotonicRandom = true
+
+	// more UUIDs than the seq field alone allows within one millisecond
+	const n = 3 * (maxSeq12 + 1)
+	prev := Nil
+	for i := 0; i < n; i++ {
+		u, err := g.NewV7(MillisecondPrecision)
+		if err != nil {
+			t.Fatalf("NewV7() #%d: %v", i, err)
+		}
+		if u.Version() != V7 || u.Variant() != VariantRFC4122 {
+			t.Fatalf("%v has version %d and variant %d", u, u.Version(), u.Variant())
+		}
+		if ts, _ := TimeFromV7(u, MillisecondPrecision); !ts.Equal(now) {
+			t.Fatalf("TimeFromV7(%v) = %v, want %v", u, ts, now)
+		}
+		if Compare(prev, u) >= 0 {
+			t.Fatalf("NewV7() #%d = %v, not after %v", i, u, prev)
+		}
+		prev = u
+	}
+
+	// the last timestamp is reused when the clock moves backwards
+	now = now.Add(-time.Second)
+	u := Must(g.NewV7(MillisecondPrecision))
+	if Compare(prev, u) >= 0 {
+		t.Errorf("NewV7() after a clock rollback = %v, not after %v", u, prev)
+	}
+	prev = u
+
+	// and the random bits are redrawn in a new millisecond
+	now = now.Add(2 * time.Second)
+	u = Must(g.NewV7(MillisecondPrecision))
+	if ts, _ := TimeFromV7(u, MillisecondPrecision); !ts.Equal(now) {
+		t.Errorf("TimeFromV7(%v) = %v, want %v", u, ts, now)
+	}
+	if Compare(prev, u) >= 0 {
+		t.Errorf("NewV7() in a new millisecond = %v, not after %v", u, prev)
+	}
+
+	// random bits at their maximum cannot be incremented
+	g = NewGenWithOptions(WithEpochFunc(func() time.Time { return now }), WithRandomReader(onesReader{}))
+	g.MonotonicRandom = true
+	if u := Must(g.NewV7(MillisecondPrecision)); u != Must(FromString("061a0d47-1315-7fff-bfff-ffffffffffff")) {
+		t.Errorf("NewV7() with all random bits set = %v", u)
+	}
+	u, err := g.NewV7(MillisecondPrecision)
+	testErrCheck(t, "NewV7()", "too fast", err)
+	if u != Nil {
+		t.Errorf("NewV7() = %v on error, want Nil", u)
+	}
+
+	g = NewGenWithOptions(WithRandomReader(&faultyReader{}))
+	g.MonotonicRandom = true
+	if _, err := g.NewV7(MillisecondPrecision); err == nil {
+		t.Error("NewV7() with a faulty reader: want error")
+	}
+}
+
+func testNewV7SubMillisecondFraction(t *testing.T) {
+	now := time.Date(2021, 11, 26, 12, 34, 56, 123456789, time.UTC)
+	g := NewGenWithOptions(WithEpochFunc(func() time.Time { return now }), WithSubMillisecondFraction())
+	if !g.SubMillisecondFraction {
+		t.Fatal("WithSubMillisecondFraction() did not set SubMillisecondFraction")
+	}
+
+	// 48-bit unix_ts_ms of 0x017d5c3dd5fb and a fraction of 456789ns * 4096 / 1ms
+	// = 0x74f, which is incremented while the clock is unchanged
+	for i, want := range []string{"017d5c3d-d5fb-774f", "017d5c3d-d5fb-7750"} {
+		u := Must(g.NewV7(MillisecondPrecision))
+		if got := u.String()[:18]; got != want {
+			t.Errorf("NewV7() #%d = %v, want prefix %s", i, u, want)
+		}
+		if u.Version() != V7 || u.Variant() != VariantRFC4122 {
+			t.Errorf("%v has version %d and variant %d", u, u.Version(), u.Variant())
+		}
+	}
+
+	// the fraction carries into the millisecond, and the last value is
+	// reused when the clock moves backwards
+	now = time.Date(2021, 11, 26, 12, 34, 56, 124999999, time.UTC)
+	prev := Must(g.NewV7(MillisecondPrecision))
+	if got, want := prev.String()[:18], "017d5c3d-d5fc-7fff"; got != want {
+		t.Errorf("NewV7() at the end of a millisecond = %v, want prefix %s", prev, want)
+	}
+	for i, want := range []string{"017d5c3d-d5fd-7000", "017d5c3d-d5fd-7001"} {
+		u := Must(g.NewV7(MillisecondPrecision))
+		if got := u.String()[:18]; got != want {
+			t.Errorf("NewV7() #%d after the fraction overflowed = %v, want prefix %s", i, u, want)
+		}
+		if Compare(prev, u) >= 0 {
+			t.Errorf("NewV7() #%d = %v, not after %v", i, u, prev)
+		}
+		prev = u
+		now = now.Add(-time.Second)
+	}
+
+	// other precisions are not affected
+	now = now.Add(time.Hour)
+	u := Must(g.NewV7(NanosecondPrecision))
+	if ts, _ := TimeFromV7(u, NanosecondPrecision); !ts.Equal(now) {
+		t.Errorf("TimeFromV7(NewV7(NanosecondPrecision)) = %v, want %v", ts, now)
+	}
+
+	g = NewGenWithOptions(WithSubMillisecondFraction(), WithRandomReader(&faultyReader{}))
+	if _, err := g.NewV7(MillisecondPrecision); err == nil {
+		t.Error("NewV7() with a faulty reader: want error")
+	}
+	g = NewGenWithOptions(WithSubMillisecondFraction(), WithEpochFunc(func() time.Time { return time.Unix(-1, 0) }))
+	if _, err := g.NewV7(MillisecondPrecision); err == nil {
+		t.Error("NewV7() before 1970: want error")
+	}
+}
+
+func testGenerateV7Over(t *testing.T) {
+	start := time.Date(2021, 11, 26, 0, 0, 0, 0, time.UTC)
+	end := start.Add(24 * time.Hour)
+
+	t.Run("Spread", func(t *testing.T) {
+		const n = 1000
+		us, err := GenerateV7Over(start, end, n)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(us) != n {
+			t.Fatalf("len(GenerateV7Over()) = %d, want %d", len(us), n)
+		}
+		for i, u := range us {
+			if u.Version() != V7 || u.Variant() != VariantRFC4122 {
+				t.Fatalf("%v has version %d and variant %d", u, u.Version(), u.Variant())
+			}
+			ts, err := TimeFromV7(u, MillisecondPrecision)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if ts.Before(start) || ts.After(end) {
+				t.Fatalf("GenerateV7Over()[%d] time %v outside [%v, %v]", i, ts, start, end)
+			}
+			if i > 0 && Compare(us[i-1], u) >= 0 {
+				t.Fatalf("GenerateV7Over() not monotonic at %d: %v >= %v", i, us[i-1], u)
+			}
+		}
+		first, _ := TimeFromV7(us[0], MillisecondPrecision)
+		last, _ := TimeFromV7(us[n-1], MillisecondPrecision)
+		if !first.Equal(start) || !last.Equal(end) {
+			t.Errorf("GenerateV7Over() spans [%v, %v], want [%v, %v]", first, last, start, end)
+		}
+	})
+
+	t.Run("SameMillisecond", func(t *testing.T) {
+		us, err := GenerateV7Over(start, start.Add(500*time.Microsecond), 100)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := 1; i < len(us); i++ {
+			if Compare(us[i-1], us[i]) >= 0 {
+				t.Fatalf("GenerateV7Over() not monotonic at %d: %v >= %v", i, us[i-1], us[i])
+			}
+		}
+		if _, err := GenerateV7Over(start, start, maxSeq12+2); err == nil {
+			t.Error("GenerateV7Over() with too many UUIDs in one millisecond: want error")
+		}
+	})
+
+	t.Run("Edges", func(t *testing.T) {
+		for _, n := range []int{0, 1} {
+			us, err := GenerateV7Over(start, end, n)
+			if err != nil || len(us) != n {
+				t.Errorf("GenerateV7Over(%d) = %v, %v", n, us, err)
+			}
+		}
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		if _, err := GenerateV7Over(end, start, 10); err == nil {
+			t.Error("GenerateV7Over() with end before start: want error")
+		}
+		if _, err := GenerateV7Over(start, end, -1); err == nil {
+			t.Error("GenerateV7Over(-1): want error")
+		}
+		if _, err := GenerateV7Over(time.Unix(-1, 0), end, 10); err == nil {
+			t.Error("GenerateV7Over() before the Unix epoch: want error")
+		}
+		g := &Gen{epochFunc: time.Now, rand: &faultyReader{readToFail: 0}}
+		if _, err := g.GenerateV7Over(start, end, 10); err == nil {
+			t.Error("GenerateV7Over() with a faulty reader: want error")
+		}
+	})
+}
+
+func testNewV7MaxFutureSkew(t *testing.T) {
+	now := time.Date(2021, 11, 26, 12, 34, 56, 0, time.UTC)
+	mono := now
+	g := NewGen()
+	g.epochFunc = func() time.Time { return now }
+	g.monoFunc = func() time.Time { return mono }
+	g.MaxFutureSkew = time.Minute
+
+	// the first UUID is never rejected
+	if _, err := g.NewV7(MillisecondPrecision); err != nil {
+		t.Fatal(err)
+	}
+
+	now = now.Add(time.Minute) // exactly at the threshold
+	if _, err := g.NewV7(MillisecondPrecision); err != nil {
+		t.Fatalf("NewV7() with a skew of %v: %v", g.MaxFutureSkew, err)
+	}
+
+	// every call fails while the clock stays ahead
+	now = now.Add(time.Minute + 10*time.Millisecond)
+	for i := 0; i < 2; i++ {
+		mono = mono.Add(time.Millisecond)
+		u, err := g.NewV7(MillisecondPrecision)
+		testErrCheck(t, "NewV7()", "exceeding MaxFutureSkew", err)
+		if u != Nil {
+			t.Errorf("NewV7() #%d = %v on error, want Nil", i, u)
+		}
+	}
+	g.MonotonicRandom = true
+	if _, err := g.NewV7(MillisecondPrecision); err == nil {
+		t.Error("NewV7() with MonotonicRandom after a clock jump: want error")
+	}
+	g.MonotonicRandom = false
+
+	// and succeeds again once the clock is corrected
+	now = now.Add(-time.Minute)
+	if _, err := g.NewV7(MillisecondPrecision); err != nil {
+		t.Fatalf("NewV7() after the clock was corrected: %v", err)
+	}
+
+	// an idle period is not a jump
+	now, mono = now.Add(time.Hour), mono.Add(time.Hour)
+	for i := 0; i < 2; i++ {
+		if _, err := g.NewV7(MillisecondPrecision); err != nil {
+			t.Fatalf("NewV7() #%d after an idle period: %v", i, err)
+		}
+	}
+
+	g.MaxFutureSkew = 0
+	now = now.Add(24 * time.Hour)
+	if _, err := g.NewV7(MillisecondPrecision); err != nil {
+		t.Fatalf("NewV7() with the guard disabled: %v", err)
+	}
+}
+
+func testNewV7Batch(t *testing.T) {
+	now := time.Date(2022, 3, 4, 5, 6, 7, 891234567, time.UTC)
+	g := NewGenWithOptions(WithEpochFunc(func() time.Time { return now }))
+
+	// large enough to spill into the following milliseconds
+	const n = 3*(maxSeq12+1) - 10
+	us, err := g.NewV7Batch(n)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(us) != n {
+		t.Fatalf("len(NewV7Batch(%d)) = %d", n, len(us))
+	}
+	for i, u := range us {
+		if u.Version() != V7 || u.Variant() != VariantRFC4122 {
+			t.Fatalf("%v has version %d and variant %d", u, u.Version(), u.Variant())
+		}
+		if i > 0 && Compare(us[i-1], u) >= 0 {
+			t.Fatalf("batch not monotonic at %d: %v >= %v", i, us[i-1], u)
+		}
+	}
+	first, _ := TimeFromV7(us[0], MillisecondPrecision)
+	last, _ := TimeFromV7(us[n-1], MillisecondPrecision)
+	if want := now.Truncate(time.Millisecond); !first.Equal(want) {
+		t.Errorf("first UUID time = %v, want %v", first, want)
+	}
+	if want := now.Truncate(time.Millisecond).Add(2 * time.Millisecond); !last.Equal(want) {
+		t.Errorf("last UUID time = %v, want %v", last, want)
+	}
+
+	// a later batch and NewV7 continue after the batch
+	now = now.Add(2 * time.Millisecond)
+	next, err := g.NewV7Batch(5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if Compare(us[n-1], next[0]) >= 0 {
+		t.Errorf("second batch starts at %v, not after %v", next[0], us[n-1])
+	}
+	u := Must(g.NewV7(MillisecondPrecision))
+	if Compare(next[4], u) >= 0 {
+		t.Errorf("NewV7() = %v, not after batch %v", u, next[4])
+	}
+	next, err = g.NewV7Batch(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if Compare(u, next[0]) >= 0 {
+		t.Errorf("NewV7Batch() = %v, not after NewV7() %v", next[0], u)
+	}
+
+	// NewV7 sorts after a batch that spilled past the unchanged clock
+	for _, monotonic := range []bool{false, true} {
+		g := NewGenWithOptions(WithEpochFunc(func() time.Time { return now }))
+		g.MonotonicRandom = monotonic
+		us, err := g.NewV7Batch(maxSeq12 + 1 + 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		last := us[len(us)-1]
+		for i := 0; i < 3; i++ {
+			u := Must(g.NewV7(MillisecondPrecision))
+			if Compare(last, u) >= 0 {
+				t.Fatalf("MonotonicRandom=%t: NewV7() #%d = %v, not after batch %v", monotonic, i, u, last)
+			}
+			last = u
+		}
+	}
+
+	if us, err := NewV7Batch(0); err != nil || len(us) != 0 {
+		t.Errorf("NewV7Batch(0) = %v, %v, want no UUIDs", us, err)
+	}
+	if _, err := NewV7Batch(-1); err == nil {
+		t.Error("NewV7Batch(-1): want error")
+	}
+	g = NewGenWithOptions(WithRandomReader(&faultyReader{}))
+	if _, err := g.NewV7Batch(10); err == nil {
+		t.Error("NewV7Batch() with a faulty reader: want error")
+	}
+}
+
+func testNewV7BatchAt(t *testing.T) {
+	ts := time.Date(2022, 3, 4, 5, 6, 7, 891234567, time.UTC)
+
+	t.Run("Basic", func(t *testing.T) {
+		g := NewGen()
+		us, err := g.NewV7BatchAt(ts, maxSeq12+1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(us) != maxSeq12+1 {
+			t.Fatalf("len(NewV7BatchAt()) = %d, want %d", len(us), maxSeq12+1)
+		}
+
+		want := ts.Truncate(time.Millisecond)
+		seen := make(map[UUID]bool, len(us))
+		for i, u := range us {
+			if v := u.Version(); v != V7 {
+				t.Fatalf("%v.Version() = %d, want %d", u, v, V7)
+			}
+			if v := u.Variant(); v != VariantRFC4122 {
+				t.Fatalf("%v.Variant() = %d, want %d", u, v, VariantRFC4122)
+			}
+			tt, err := TimeFromV7(u, MillisecondPrecision)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !tt.Equal(want) {
+				t.Fatalf("TimeFromV7(%v) = %v, want %v", u, tt, want)
+			}
+			if i > 0 && Compare(us[i-1], u) >= 0 {
+				t.Fatalf("batch not monotonic at %d: %v >= %v", i, us[i-1], u)
+			}
+			if seen[u] {
+				t.Fatalf("duplicate UUID in batch: %v", u)
+			}
+			seen[u] = true
+		}
+	})
+
+	t.Run("Empty", func(t *testing.T) {
+		us, err := NewV7BatchAt(ts, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(us) != 0 {
+			t.Fatalf("len(NewV7BatchAt(0)) = %d, want 0", len(us))
+		}
+	})
+
+	t.Run("InvalidSize", func(t *testing.T) {
+		for _, n := range []int{-1, maxSeq12 + 2} {
+			_, err := NewV7BatchAt(ts, n)
+			testErrCheck(t, fmt.Sprintf("NewV7BatchAt(%d)", n), "invalid V7 batch size", err)
+		}
+	})
+
+	t.Run("InvalidTime", func(t *testing.T) {
+		_, err := NewV7BatchAt(time.Unix(-1, 0), 1)
+		testErrCheck(t, "NewV7BatchAt()", "out of range", err)
+	})
+
+	t.Run("FaultyRand", func(t *testing.T) {
+		g := &Gen{
+			epochFunc: time.Now,
+			rand:      &faultyReader{readToFail: 0},
+		}
+		us, err := g.NewV7BatchAt(ts, 8)
+		if err == nil {
+			t.Fatalf("got %v, want error", us)
+		}
+		if us != nil {
+			t.Fatalf("got %v on error, want nil", us)
+		}
+	})
+}
+
 func TestPrecision_String(t *testing.T) {
 	tests := []struct {
 		p    Precision
@@ -808,6 +2138,153 @@ func TestPrecision_Duration(t *testing.T) {
 	}
 }
 
+func TestSetDefaultGenerator(t *testing.T) {
+	data := make([]byte, 3*Size)
+	for i := range data {
+		data[i] = byte(i)
+	}
+	g := &Gen{
+		epochFunc:  time.Now,
+		hwAddrFunc: defaultHWAddrFunc,
+		rand:       bytes.NewReader(data),
+	}
+
+	prev := SetDefaultGenerator(g)
+	defer SetDefaultGenerator(prev)
+
+	if got := CurrentGenerator(); got != g {
+		t.Fatalf("CurrentGenerator() = %v, want %v", got, g)
+	}
+
+	want := []string{
+		"00010203-0405-4607-8809-0a0b0c0d0e0f",
+		"10111213-1415-4617-9819-1a1b1c1d1e1f",
+		"20212223-2425-4627-a829-2a2b2c2d2e2f",
+	}
+	for i, w := range want {
+		u, err := NewV4()
+		if err != nil {
+			t.Fatalf("NewV4() #%d: %v", i, err)
+		}
+		if got := u.String(); got != w {
+			t.Errorf("NewV4() #%d = %s, want %s", i, got, w)
+		}
+	}
+	if u, err := NewV4(); err == nil {
+		t.Errorf("NewV4() with exhausted reader = %v, want error", u)
+	}
+
+	if got := SetDefaultGenerator(prev); got != g {
+		t.Errorf("SetDefaultGenerator() returned %v, want %v", got, g)
+	}
+}
+
+func TestSetDefaultGeneratorNotGen(t *testing.T) {
+	data := make([]byte, 3*Size)
+	for i := range data {
+		data[i] = byte(i)
+	}
+	g := NewLockFreeGen(WithRandomReader(bytes.NewReader(data)))
+	defer SetDefaultGenerator(SetDefaultGenerator(g))
+
+	// the batch functions fall back to the default's NewV4
+	us, err := NewV4Batch(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dst := make([]UUID, 1)
+	if err := FillV4(dst); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"00010203-0405-4607-8809-0a0b0c0d0e0f",
+		"10111213-1415-4617-9819-1a1b1c1d1e1f",
+		"20212223-2425-4627-a829-2a2b2c2d2e2f",
+	}
+	for i, u := range append(us, dst...) {
+		if got := u.String(); got != want[i] {
+			t.Errorf("UUID #%d = %s, want %s", i, got, want[i])
+		}
+	}
+	if err := FillV4(dst); err == nil {
+		t.Error("FillV4() with exhausted reader: want error")
+	}
+
+	// and the rest report that the default cannot serve them
+	now := time.Now()
+	errs := map[string]error{}
+	_, errs["NewV1At"] = NewV1At(now)
+	_, errs["NewV6At"] = NewV6At(now)
+	_, errs["NewV7At"] = NewV7At(now, MillisecondPrecision)
+	_, errs["NewV7Batch"] = NewV7Batch(1)
+	_, errs["NewV7BatchAt"] = NewV7BatchAt(now, 1)
+	_, errs["GenerateV7Over"] = GenerateV7Over(now, now, 1)
+	_, errs["NewCOMB"] = NewCOMB()
+	for name, err := range errs {
+		testErrCheck(t, name+"()", "*uuid.LockFreeGen does not implement "+name, err)
+	}
+}
+
+func TestDefaultGeneratorAssignment(t *testing.T) {
+	prev := DefaultGenerator
+	defer func() { DefaultGenerator = prev }()
+
+	// assigning the variable directly still replaces the default
+	g := NewGenWithOptions(WithRandomReader(bytes.NewReader(make([]byte, Size))))
+	DefaultGenerator = g
+	if got := CurrentGenerator(); got != g {
+		t.Fatalf("CurrentGenerator() = %v, want %v", got, g)
+	}
+	u, err := NewV4()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := u.String(), "00000000-0000-4000-8000-000000000000"; got != want {
+		t.Errorf("NewV4() = %s, want %s", got, want)
+	}
+	if got := SetDefaultGenerator(prev); got != g {
+		t.Errorf("SetDefaultGenerator() returned %v, want %v", got, g)
+	}
+	if DefaultGenerator != prev {
+		t.Errorf("DefaultGenerator = %v after SetDefaultGenerator, want %v", DefaultGenerator, prev)
+	}
+}
+
+func TestSetDefaultGeneratorNil(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("SetDefaultGenerator(nil) did not panic")
+		}
+		if DefaultGenerator == nil {
+			t.Error("SetDefaultGenerator(nil) replaced DefaultGenerator")
+		}
+	}()
+	SetDefaultGenerator(nil)
+}
+
+func TestSetDefaultGeneratorConcurrent(t *testing.T) {
+	defer SetDefaultGenerator(CurrentGenerator())
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			SetDefaultGenerator(NewGen())
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			if CurrentGenerator() == nil {
+				t.Error("CurrentGenerator() = nil")
+				return
+			}
+		}
+	}()
+	wg.Wait()
+}
+
 func BenchmarkGenerator(b *testing.B) {
 	b.Run("NewV1", func(b *testing.B) {
 		for i := 0; i < b.N; i++ {
@@ -824,6 +2301,13 @@ func BenchmarkGenerator(b *testing.B) {
 			NewV4()
 		}
 	})
+	b.Run("NewV4Batch", func(b *testing.B) {
+		dst := make([]UUID, 1024)
+		b.SetBytes(int64(len(dst) * Size))
+		for i := 0; i < b.N; i++ {
+			FillV4(dst)
+		}
+	})
 	b.Run("NewV5", func(b *testing.B) {
 		for i := 0; i < b.N; i++ {
 			NewV5(NamespaceDNS, "www.example.com")
@@ -831,6 +2315,27 @@ func BenchmarkGenerator(b *testing.B) {
 	})
 }
 
+// BenchmarkNewV4Parallel measures V4 generation under contention, run it with
+// the -cpu flag to see how throughput scales with GOMAXPROCS.
+func BenchmarkNewV4Parallel(b *testing.B) {
+	b.Run("Uncached", func(b *testing.B) {
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				NewV4()
+			}
+		})
+	})
+	b.Run("Cached", func(b *testing.B) {
+		EnableRandPool()
+		defer DisableRandPool()
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				NewV4()
+			}
+		})
+	})
+}
+
 type faultyReader struct {
 	callsNum   int
 	readToFail int // Read call number to fail
@@ -0,0 +1,210 @@
+// Copyright (C) 2013-2018 by Maxim Bublis <b@codemonkey.ru>
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the
+// "Software"), to deal in the Software without restriction, including
+// without limitation the rights to use, copy, modify, merge, publish,
+// distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to
+// the following conditions:
+//
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+// MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION
+// WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package uuid
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"sync"
+	"sync/atomic"
+)
+
+// LockFreeGen is a Generator for many-core machines, whose V1, V6, and
+// millisecond precision V7 clock state is kept in atomic words that are
+// updated with compare-and-swap instead of behind a mutex, so that
+// concurrent callers never block each other.
+//
+// Instead of counting up a clock sequence when the clock has not advanced,
+// each UUID takes the next timestamp after the previous one: V1 and V6 UUIDs
+// advance by one 100-nanosecond tick, and V7 UUIDs use the seq field of the
+// millisecond, moving on to the next millisecond when it is exhausted. The
+// UUIDs are therefore strictly increasing per generator, but under sustained
+// bursts their timestamps can run slightly ahead of the clock. The V1 and V6
+// clock sequence is chosen randomly once.
+//
+// V3, V4, V5, and microsecond and nanosecond precision V7 UUIDs are generated
+// by an ordinary Gen configured with the same options.
+//
+// The clock state is never persisted, so WithStableStorage has no effect on
+// V1 and V6 UUIDs. Millisecond precision V7 UUIDs always use the seq field
+// as described above: the Gen fields MaxFutureSkew, UniqueTail,
+// MonotonicRandom, and SubMillisecondFraction, which cannot be set on a
+// LockFreeGen, and the WithSubMillisecondFraction option are ignored.
+type LockFreeGen struct {
+	// The atomic words come first so that they are 64-bit aligned on 32-bit
+	// platforms, see the bugs section of the sync/atomic docs.
+	v1LastTime uint64 // atomic: last V1/V6 timestamp
+	v7Last     uint64 // atomic: last V7 (unix ms << 12 | seq)
+
+	gen *Gen
+
+	clockSeqMutex sync.Mutex
+	clockSeqSet   uint32 // atomic: clockSeq has been read
+	clockSeq      uint16
+}
+
+// interface check -- build will fail if *LockFreeGen doesn't satisfy Generator
+var _ Generator = (*LockFreeGen)(nil)
+
+// NewLockFreeGen returns a new LockFreeGen configured with opts, which have the
+// same meaning as for NewGenWithOptions.
+func NewLockFreeGen(opts ...GenOption) *LockFreeGen {
+	return &LockFreeGen{gen: NewGenWithOptions(opts...)}
+}
+
+// nextV1Time returns a V1 timestamp that is after all previously returned.
+func (g *LockFreeGen) nextV1Time() uint64 {
+	now := g.gen.getEpoch()
+	for {
+		last := atomic.LoadUint64(&g.v1LastTime)
+		next := now
+		if next <= last {
+			next = last + 1
+		}
+		if atomic.CompareAndSwapUint64(&g.v1LastTime, last, next) {
+			return next
+		}
+	}
+}
+
+// getClockSequence returns the V1 and V6 clock sequence, reading it from the
+// random source on first use. A failed read is retried by the next call.
+func (g *LockFreeGen) getClockSequence() (uint16, error) {
+	if atomic.LoadUint32(&g.clockSeqSet) == 1 {
+		return g.clockSeq, nil
+	}
+
+	g.clockSeqMutex.Lock()
+	defer g.clockSeqMutex.Unlock()
+
+	if g.clockSeqSet == 0 {
+		var buf [2]byte
+		if _, err := io.ReadFull(g.gen.rand, buf[:]); err != nil {
+			return 0, err
+		}
+		g.clockSeq = binary.BigEndian.Uint16(buf[:])
+		atomic.StoreUint32(&g.clockSeqSet, 1)
+	}
+	return g.clockSeq, nil
+}
+
+// NewV1 returns a UUID based on the current timestamp and MAC address.
+func (g *LockFreeGen) NewV1() (UUID, error) {
+	u := UUID{}
+
+	clockSeq, err := g.getClockSequence()
+	if err != nil {
+		return Nil, err
+	}
+	hardwareAddr, err := g.gen.getHardwareAddr()
+	if err != nil {
+		return Nil, err
+	}
+
+	putV1Timestamp(&u, g.nextV1Time())
+	binary.BigEndian.PutUint16(u[8:], clockSeq)
+	copy(u[10:], hardwareAddr)
+
+	u.SetVersion(V1)
+	u.SetVariant(VariantRFC4122)
+
+	return u, nil
+}
+
+// NewV3 returns a UUID based on the MD5 hash of the namespace UUID and name.
+func (g *LockFreeGen) NewV3(ns UUID, name string) UUID {
+	return g.gen.NewV3(ns, name)
+}
+
+// NewV4 returns a randomly generated UUID.
+func (g *LockFreeGen) NewV4() (UUID, error) {
+	return g.gen.NewV4()
+}
+
+// NewV5 returns a UUID based on SHA-1 hash of the namespace UUID and name.
+func (g *LockFreeGen) NewV5(ns UUID, name string) UUID {
+	return g.gen.NewV5(ns, name)
+}
+
+// NewV6 returns a k-sortable UUID based on a timestamp and 48 bits of
+// pseudorandom data, see Gen.NewV6.
+func (g *LockFreeGen) NewV6() (UUID, error) {
+	var u UUID
+
+	clockSeq, err := g.getClockSequence()
+	if err != nil {
+		return Nil, err
+	}
+	if g.gen.nodeID != nil {
+		copy(u[10:], g.gen.nodeID[:])
+	} else if _, err := io.ReadFull(g.gen.rand, u[10:]); err != nil {
+		return Nil, err
+	}
+
+	putV6Timestamp(&u, g.nextV1Time())
+	binary.BigEndian.PutUint16(u[8:], clockSeq&0x3fff) // set clk_seq_hi_res (minus two variant bits)
+
+	u.SetVersion(V6)
+	u.SetVariant(VariantRFC4122)
+
+	return u, nil
+}
+
+// NewV7 returns a k-sortable UUID based on the current UNIX epoch, see
+// Gen.NewV7. Only MillisecondPrecision UUIDs are generated lock-free, and the
+// other precisions have their own clock sequence, so V7 UUIDs of different
+// precisions are not ordered relative to each other.
+func (g *LockFreeGen) NewV7(p Precision) (UUID, error) {
+	if p != MillisecondPrecision {
+		return g.gen.NewV7(p)
+	}
+
+	var u UUID
+	if _, err := io.ReadFull(g.gen.rand, u[8:]); err != nil {
+		return Nil, err
+	}
+
+	tn := g.gen.epochFunc()
+	if tn.Unix() < 0 || tn.Unix() >= 1<<36 {
+		return Nil, fmt.Errorf("uuid: time %v out of range for V7", tn)
+	}
+	now := (uint64(tn.Unix())*1000 + uint64(tn.Nanosecond()/1000000)) << 12
+	var next uint64
+	for {
+		last := atomic.LoadUint64(&g.v7Last)
+		next = now
+		if next <= last {
+			next = last + 1
+		}
+		if atomic.CompareAndSwapUint64(&g.v7Last, last, next) {
+			break
+		}
+	}
+
+	ms := next >> 12
+	putV7Milli(&u, ms/1000, ms%1000, uint16(next&maxSeq12))
+	u.SetVersion(V7)
+	u.SetVariant(VariantRFC4122)
+
+	return u, nil
+}
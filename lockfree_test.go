@@ -0,0 +1,189 @@
+// Copyright (C) 2013-2018 by Maxim Bublis <b@codemonkey.ru>
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the
+// "Software"), to deal in the Software without restriction, including
+// without limitation the rights to use, copy, modify, merge, publish,
+// distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to
+// the following conditions:
+//
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+// MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION
+// WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package uuid
+
+import (
+	"bytes"
+	"encoding/binary"
+	"net"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestLockFreeGen(t *testing.T) {
+	now := time.Date(2021, 11, 26, 12, 34, 56, 789000000, time.UTC)
+	addr := net.HardwareAddr{0, 1, 2, 3, 4, 42}
+	g := NewLockFreeGen(
+		WithEpochFunc(func() time.Time { return now }),
+		WithHWAddrFunc(func() (net.HardwareAddr, error) { return addr, nil }),
+	)
+
+	t.Run("Versions", func(t *testing.T) {
+		tests := []struct {
+			version byte
+			fn      func() (UUID, error)
+		}{
+			{V1, g.NewV1},
+			{V4, g.NewV4},
+			{V6, g.NewV6},
+			{V7, func() (UUID, error) { return g.NewV7(MillisecondPrecision) }},
+			{V7, func() (UUID, error) { return g.NewV7(MicrosecondPrecision) }},
+		}
+		for _, tt := range tests {
+			u, err := tt.fn()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if u.Version() != tt.version || u.Variant() != VariantRFC4122 {
+				t.Errorf("%v has version %d and variant %d, want version %d", u, u.Version(), u.Variant(), tt.version)
+			}
+		}
+		if u := g.NewV3(NamespaceDNS, "www.example.com"); u != NewV3(NamespaceDNS, "www.example.com") {
+			t.Errorf("NewV3() = %v, want %v", u, NewV3(NamespaceDNS, "www.example.com"))
+		}
+		if u := g.NewV5(NamespaceDNS, "www.example.com"); u != NewV5(NamespaceDNS, "www.example.com") {
+			t.Errorf("NewV5() = %v, want %v", u, NewV5(NamespaceDNS, "www.example.com"))
+		}
+		u := Must(g.NewV1())
+		if !bytes.Equal(u[10:], addr) {
+			t.Errorf("node = %v, want %v", net.HardwareAddr(u[10:]), addr)
+		}
+	})
+
+	// with a fixed clock, concurrent callers still get strictly increasing
+	// timestamps that spill over into the following milliseconds
+	t.Run("Concurrent", func(t *testing.T) {
+		const goroutines = 4
+		const n = 2 * (maxSeq12 + 1)
+		for _, fn := range []func() (UUID, error){
+			g.NewV1,
+			g.NewV6,
+			func() (UUID, error) { return g.NewV7(MillisecondPrecision) },
+		} {
+			results := make([][]UUID, goroutines)
+			var wg sync.WaitGroup
+			for i := range results {
+				wg.Add(1)
+				go func(i int) {
+					defer wg.Done()
+					us := make([]UUID, n)
+					for j := range us {
+						us[j] = Must(fn())
+					}
+					results[i] = us
+				}(i)
+			}
+			wg.Wait()
+
+			seen := make(map[UUID]bool, goroutines*n)
+			for _, us := range results {
+				for j, u := range us {
+					// V1 UUIDs do not sort by their timestamp
+					if j > 0 && u.Version() != V1 && Compare(us[j-1], u) >= 0 {
+						t.Fatalf("V%d UUIDs not increasing: %v then %v", u.Version(), us[j-1], u)
+					}
+					if seen[u] {
+						t.Fatalf("duplicate V%d UUID: %v", u.Version(), u)
+					}
+					seen[u] = true
+				}
+			}
+		}
+	})
+
+	t.Run("ClockAdvances", func(t *testing.T) {
+		now = now.Add(time.Hour)
+		u := Must(g.NewV7(MillisecondPrecision))
+		if ts, _ := u.Time(); !ts.Equal(now) {
+			t.Errorf("%v.Time() = %v, want %v", u, ts, now)
+		}
+		if seq := u[7]; seq != 0 || u[6]&0x0f != 0 {
+			t.Errorf("%v has a non-zero seq in a new millisecond", u)
+		}
+		u = Must(g.NewV6())
+		if ts, _ := u.Time(); !ts.Equal(now) {
+			t.Errorf("%v.Time() = %v, want %v", u, ts, now)
+		}
+	})
+
+	t.Run("FaultyRand", func(t *testing.T) {
+		g := NewLockFreeGen(WithRandomReader(&faultyReader{}))
+		if _, err := g.NewV7(MillisecondPrecision); err == nil {
+			t.Error("NewV7() with a faulty reader: want error")
+		}
+		g = NewLockFreeGen(WithRandomReader(&faultyReader{}))
+		if _, err := g.NewV1(); err == nil {
+			t.Error("NewV1() with a faulty reader: want error")
+		}
+
+		// the clock sequence is read again after a failure
+		r := &faultyReader{}
+		g = NewLockFreeGen(WithRandomReader(r), WithNodeID([6]byte{1, 2, 3, 4, 5, 6}))
+		if _, err := g.NewV6(); err == nil {
+			t.Error("NewV6() with a faulty reader: want error")
+		}
+		u, err := g.NewV1()
+		if err != nil {
+			t.Fatalf("NewV1() after a failed read: %v", err)
+		}
+		if r.callsNum != 2 {
+			t.Errorf("random source read %d times, want 2", r.callsNum)
+		}
+		if got := binary.BigEndian.Uint16(u[8:]) & 0x3fff; got != g.clockSeq&0x3fff {
+			t.Errorf("NewV1() clock sequence = %d, want %d", got, g.clockSeq&0x3fff)
+		}
+		if _, err := g.NewV1(); err != nil || r.callsNum != 2 {
+			t.Errorf("NewV1() = %v and %d reads, want the clock sequence to be kept", err, r.callsNum)
+		}
+	})
+}
+
+// BenchmarkLockFreeGen compares the generation of time-based UUIDs by Gen
+// and LockFreeGen under contention, run it with the -cpu flag to see how
+// throughput scales with GOMAXPROCS.
+func BenchmarkLockFreeGen(b *testing.B) {
+	gens := []struct {
+		name string
+		g    Generator
+	}{
+		{"Gen", NewGen()},
+		{"LockFreeGen", NewLockFreeGen()},
+	}
+	for _, gen := range gens {
+		g := gen.g
+		b.Run(gen.name+"/NewV6", func(b *testing.B) {
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					g.NewV6()
+				}
+			})
+		})
+		b.Run(gen.name+"/NewV7", func(b *testing.B) {
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					g.NewV7(MillisecondPrecision)
+				}
+			})
+		})
+	}
+}
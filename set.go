@@ -0,0 +1,98 @@
+// Copyright (C) 2013-2018 by Maxim Bublis <b@codemonkey.ru>
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the
+// "Software"), to deal in the Software without restriction, including
+// without limitation the rights to use, copy, modify, merge, publish,
+// distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to
+// the following conditions:
+//
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+// MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION
+// WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package uuid
+
+import "sort"
+
+// Set is a set of UUIDs. The zero value is not usable, create sets with
+// NewSet.
+type Set struct {
+	m map[UUID]struct{}
+}
+
+// NewSet returns a set containing the UUIDs in us.
+func NewSet(us ...UUID) Set {
+	s := Set{m: make(map[UUID]struct{}, len(us))}
+	for _, u := range us {
+		s.m[u] = struct{}{}
+	}
+	return s
+}
+
+// Add adds u to the set, reporting whether it was not already present.
+func (s Set) Add(u UUID) bool {
+	if _, ok := s.m[u]; ok {
+		return false
+	}
+	s.m[u] = struct{}{}
+	return true
+}
+
+// Contains reports whether u is in the set.
+func (s Set) Contains(u UUID) bool {
+	_, ok := s.m[u]
+	return ok
+}
+
+// Len returns the number of UUIDs in the set.
+func (s Set) Len() int {
+	return len(s.m)
+}
+
+// Slice returns the UUIDs in the set, sorted by Compare.
+func (s Set) Slice() []UUID {
+	us := make([]UUID, 0, len(s.m))
+	for u := range s.m {
+		us = append(us, u)
+	}
+	sort.Slice(us, func(i, j int) bool {
+		return Compare(us[i], us[j]) < 0
+	})
+	return us
+}
+
+// Union returns a new set of the UUIDs that are in s, other, or both.
+func (s Set) Union(other Set) Set {
+	out := Set{m: make(map[UUID]struct{}, len(s.m)+len(other.m))}
+	for u := range s.m {
+		out.m[u] = struct{}{}
+	}
+	for u := range other.m {
+		out.m[u] = struct{}{}
+	}
+	return out
+}
+
+// Intersect returns a new set of the UUIDs that are in both s and other.
+func (s Set) Intersect(other Set) Set {
+	small, large := s, other
+	if len(small.m) > len(large.m) {
+		small, large = large, small
+	}
+	out := Set{m: make(map[UUID]struct{})}
+	for u := range small.m {
+		if _, ok := large.m[u]; ok {
+			out.m[u] = struct{}{}
+		}
+	}
+	return out
+}
@@ -0,0 +1,113 @@
+// Copyright (C) 2013-2018 by Maxim Bublis <b@codemonkey.ru>
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the
+// "Software"), to deal in the Software without restriction, including
+// without limitation the rights to use, copy, modify, merge, publish,
+// distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to
+// the following conditions:
+//
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+// MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION
+// WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package uuid
+
+import "testing"
+
+func TestSet(t *testing.T) {
+	t.Run("AddContains", testSetAddContains)
+	t.Run("Slice", testSetSlice)
+	t.Run("Union", testSetUnion)
+	t.Run("Intersect", testSetIntersect)
+}
+
+func testSetAddContains(t *testing.T) {
+	s := NewSet()
+	if s.Len() != 0 || s.Contains(Nil) {
+		t.Fatalf("NewSet() is not empty: %v", s.Slice())
+	}
+	if !s.Add(codecTestUUID) {
+		t.Errorf("Add(%v) = false on first add", codecTestUUID)
+	}
+	if s.Add(codecTestUUID) {
+		t.Errorf("Add(%v) = true on second add", codecTestUUID)
+	}
+	if !s.Contains(codecTestUUID) {
+		t.Errorf("Contains(%v) = false after Add", codecTestUUID)
+	}
+	if s.Contains(NamespaceURL) {
+		t.Errorf("Contains(%v) = true, never added", NamespaceURL)
+	}
+	if s.Len() != 1 {
+		t.Errorf("Len() = %d, want 1", s.Len())
+	}
+
+	s = NewSet(NamespaceDNS, NamespaceURL, NamespaceDNS)
+	if s.Len() != 2 || !s.Contains(NamespaceDNS) || !s.Contains(NamespaceURL) {
+		t.Errorf("NewSet(DNS, URL, DNS) = %v", s.Slice())
+	}
+}
+
+func testSetSlice(t *testing.T) {
+	s := NewSet(Max, NamespaceURL, Nil, NamespaceDNS)
+	want := []UUID{Nil, NamespaceDNS, NamespaceURL, Max}
+	got := s.Slice()
+	if len(got) != len(want) {
+		t.Fatalf("Slice() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("Slice() = %v, want %v", got, want)
+		}
+	}
+}
+
+func testSetUnion(t *testing.T) {
+	a := NewSet(NamespaceDNS, NamespaceURL)
+	b := NewSet(NamespaceURL, NamespaceOID)
+	u := a.Union(b)
+	if u.Len() != 3 {
+		t.Errorf("Union() = %v, want 3 elements", u.Slice())
+	}
+	for _, id := range []UUID{NamespaceDNS, NamespaceURL, NamespaceOID} {
+		if !u.Contains(id) {
+			t.Errorf("Union() = %v, missing %v", u.Slice(), id)
+		}
+	}
+	if a.Len() != 2 || b.Len() != 2 {
+		t.Errorf("Union() modified its operands: %v, %v", a.Slice(), b.Slice())
+	}
+	if got := a.Union(NewSet()); got.Len() != a.Len() {
+		t.Errorf("Union(empty) = %v, want %v", got.Slice(), a.Slice())
+	}
+}
+
+func testSetIntersect(t *testing.T) {
+	a := NewSet(NamespaceDNS, NamespaceURL, NamespaceX500)
+	b := NewSet(NamespaceURL, NamespaceOID, NamespaceX500)
+	for _, got := range []Set{a.Intersect(b), b.Intersect(a)} {
+		if got.Len() != 2 || !got.Contains(NamespaceURL) || !got.Contains(NamespaceX500) {
+			t.Errorf("Intersect() = %v, want [%v %v]", got.Slice(), NamespaceURL, NamespaceX500)
+		}
+	}
+	if got := a.Intersect(NewSet(NamespaceOID)); got.Len() != 0 {
+		t.Errorf("Intersect() of disjoint sets = %v, want empty", got.Slice())
+	}
+	if a.Len() != 3 || b.Len() != 3 {
+		t.Errorf("Intersect() modified its operands: %v, %v", a.Slice(), b.Slice())
+	}
+	got := a.Intersect(b)
+	got.Add(NamespaceDNS)
+	if b.Contains(NamespaceDNS) {
+		t.Error("Intersect() result shares storage with its operand")
+	}
+}
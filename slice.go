@@ -0,0 +1,103 @@
+// Copyright (C) 2013-2018 by Maxim Bublis <b@codemonkey.ru>
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the
+// "Software"), to deal in the Software without restriction, including
+// without limitation the rights to use, copy, modify, merge, publish,
+// distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to
+// the following conditions:
+//
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+// MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION
+// WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package uuid
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Normalize returns a sorted copy of us, ordered by Compare, with any
+// duplicate UUIDs removed. The input slice is not modified.
+func Normalize(us []UUID) []UUID {
+	seen := make(map[UUID]struct{}, len(us))
+	out := make([]UUID, 0, len(us))
+	for _, u := range us {
+		if _, ok := seen[u]; ok {
+			continue
+		}
+		seen[u] = struct{}{}
+		out = append(out, u)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return Compare(out[i], out[j]) < 0
+	})
+	return out
+}
+
+// Difference returns the UUIDs in a that are not present in b, in the order
+// they appear in a. It runs in O(len(a)+len(b)) time.
+func Difference(a, b []UUID) []UUID {
+	exclude := make(map[UUID]struct{}, len(b))
+	for _, u := range b {
+		exclude[u] = struct{}{}
+	}
+	var out []UUID
+	for _, u := range a {
+		if _, ok := exclude[u]; !ok {
+			out = append(out, u)
+		}
+	}
+	return out
+}
+
+// AllVersion reports whether every UUID in us has version v. It returns true
+// for an empty slice.
+func AllVersion(us []UUID, v byte) bool {
+	for _, u := range us {
+		if u.Version() != v {
+			return false
+		}
+	}
+	return true
+}
+
+// DetectVersions returns the number of UUIDs in us of each version present.
+func DetectVersions(us []UUID) map[byte]int {
+	versions := make(map[byte]int)
+	for _, u := range us {
+		versions[u.Version()]++
+	}
+	return versions
+}
+
+// BucketByTime groups V7 UUIDs into buckets of width d, keyed by the start of
+// each bucket as returned by Time.Truncate on the UTC timestamp of the UUID.
+// The UUIDs are assumed to have been generated with MillisecondPrecision, and
+// keep their relative order within each bucket. An error is returned if d is
+// not positive or if any element is not a V7 UUID.
+func BucketByTime(us []UUID, d time.Duration) (map[time.Time][]UUID, error) {
+	if d <= 0 {
+		return nil, fmt.Errorf("uuid: invalid bucket width %v", d)
+	}
+	buckets := make(map[time.Time][]UUID)
+	for _, u := range us {
+		t, err := TimeFromV7(u, MillisecondPrecision)
+		if err != nil {
+			return nil, err
+		}
+		key := t.UTC().Truncate(d)
+		buckets[key] = append(buckets[key], u)
+	}
+	return buckets, nil
+}
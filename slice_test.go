@@ -0,0 +1,184 @@
+// Copyright (C) 2013-2018 by Maxim Bublis <b@codemonkey.ru>
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the
+// "Software"), to deal in the Software without restriction, including
+// without limitation the rights to use, copy, modify, merge, publish,
+// distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to
+// the following conditions:
+//
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+// MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION
+// WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package uuid
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNormalize(t *testing.T) {
+	a := Must(FromString("00000000-0000-4000-8000-000000000001"))
+	b := Must(FromString("00000000-0000-4000-8000-000000000002"))
+	c := Must(FromString("ffffffff-0000-4000-8000-000000000000"))
+
+	in := []UUID{c, a, b, a, c, c, Nil}
+	orig := append([]UUID(nil), in...)
+	want := []UUID{Nil, a, b, c}
+
+	got := Normalize(in)
+	if len(got) != len(want) {
+		t.Fatalf("Normalize(%v) = %v, want %v", in, got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("Normalize(%v) = %v, want %v", in, got, want)
+		}
+	}
+	for i := range orig {
+		if in[i] != orig[i] {
+			t.Fatalf("Normalize modified its input: got %v, want %v", in, orig)
+		}
+	}
+
+	if got := Normalize(nil); len(got) != 0 {
+		t.Errorf("Normalize(nil) = %v, want empty", got)
+	}
+}
+
+func TestDifference(t *testing.T) {
+	a := Must(FromString("00000000-0000-4000-8000-000000000001"))
+	b := Must(FromString("00000000-0000-4000-8000-000000000002"))
+	c := Must(FromString("00000000-0000-4000-8000-000000000003"))
+	d := Must(FromString("00000000-0000-4000-8000-000000000004"))
+
+	tests := []struct {
+		a, b []UUID
+		want []UUID
+	}{
+		{a: []UUID{a, b, c}, b: []UUID{b, d}, want: []UUID{a, c}},
+		{a: []UUID{c, a}, b: []UUID{b, d}, want: []UUID{c, a}},
+		{a: []UUID{a, b}, b: []UUID{b, a}, want: nil},
+		{a: nil, b: []UUID{a}, want: nil},
+		{a: []UUID{a, b}, b: nil, want: []UUID{a, b}},
+	}
+	for _, tt := range tests {
+		got := Difference(tt.a, tt.b)
+		if len(got) != len(tt.want) {
+			t.Errorf("Difference(%v, %v) = %v, want %v", tt.a, tt.b, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("Difference(%v, %v) = %v, want %v", tt.a, tt.b, got, tt.want)
+				break
+			}
+		}
+	}
+}
+
+func TestAllVersion(t *testing.T) {
+	v4s := []UUID{Must(NewV4()), Must(NewV4()), Must(NewV4())}
+	mixed := append([]UUID{Must(NewV1())}, v4s...)
+
+	tests := []struct {
+		us   []UUID
+		v    byte
+		want bool
+	}{
+		{us: v4s, v: V4, want: true},
+		{us: v4s, v: V1, want: false},
+		{us: mixed, v: V4, want: false},
+		{us: []UUID{Nil}, v: 0, want: true},
+		{us: nil, v: V4, want: true},
+	}
+	for _, tt := range tests {
+		if got := AllVersion(tt.us, tt.v); got != tt.want {
+			t.Errorf("AllVersion(%v, %d) = %t, want %t", tt.us, tt.v, got, tt.want)
+		}
+	}
+}
+
+func TestDetectVersions(t *testing.T) {
+	us := []UUID{
+		Must(NewV1()),
+		Must(NewV4()),
+		Must(NewV4()),
+		NewV5(NamespaceDNS, "www.example.com"),
+		Must(NewV7(MillisecondPrecision)),
+		Nil,
+	}
+	want := map[byte]int{0: 1, V1: 1, V4: 2, V5: 1, V7: 1}
+
+	got := DetectVersions(us)
+	if len(got) != len(want) {
+		t.Fatalf("DetectVersions() = %v, want %v", got, want)
+	}
+	for v, n := range want {
+		if got[v] != n {
+			t.Errorf("DetectVersions()[%d] = %d, want %d", v, got[v], n)
+		}
+	}
+
+	if got := DetectVersions(nil); len(got) != 0 {
+		t.Errorf("DetectVersions(nil) = %v, want empty", got)
+	}
+}
+
+func TestBucketByTime(t *testing.T) {
+	start := time.Date(2021, 11, 26, 12, 0, 0, 0, time.UTC)
+	now := start
+	g := NewGen()
+	g.epochFunc = func() time.Time { return now }
+
+	// three UUIDs in each of four one-minute buckets
+	want := make(map[time.Time][]UUID)
+	var us []UUID
+	for i := 0; i < 4; i++ {
+		bucket := start.Add(time.Duration(i) * time.Minute)
+		for _, off := range []time.Duration{0, 20 * time.Second, time.Minute - time.Millisecond} {
+			now = bucket.Add(off)
+			u := Must(g.NewV7(MillisecondPrecision))
+			us = append(us, u)
+			want[bucket] = append(want[bucket], u)
+		}
+	}
+
+	got, err := BucketByTime(us, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != len(want) {
+		t.Fatalf("BucketByTime() returned %d buckets, want %d: %v", len(got), len(want), got)
+	}
+	for k, wus := range want {
+		gus := got[k]
+		if len(gus) != len(wus) {
+			t.Fatalf("BucketByTime()[%v] = %v, want %v", k, gus, wus)
+		}
+		for i := range wus {
+			if gus[i] != wus[i] {
+				t.Fatalf("BucketByTime()[%v] = %v, want %v", k, gus, wus)
+			}
+		}
+	}
+
+	if got, err := BucketByTime(nil, time.Minute); err != nil || len(got) != 0 {
+		t.Errorf("BucketByTime(nil) = %v, %v, want empty", got, err)
+	}
+	if _, err := BucketByTime(append(us, Must(NewV4())), time.Minute); err == nil {
+		t.Error("BucketByTime() with a V4 UUID: want error")
+	}
+	if _, err := BucketByTime(us, 0); err == nil {
+		t.Error("BucketByTime() with zero width: want error")
+	}
+}
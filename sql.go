@@ -36,6 +36,7 @@ func (u UUID) Value() (driver.Value, error) {
 // Scan implements the sql.Scanner interface.
 // A 16-byte slice will be handled by UnmarshalBinary, while
 // a longer byte slice or a string will be handled by UnmarshalText.
+// The version and variant bits of the scanned value are not validated.
 func (u *UUID) Scan(src interface{}) error {
 	switch src := src.(type) {
 	case UUID: // support gorm convert from UUID to NullUUID
@@ -55,6 +56,33 @@ func (u *UUID) Scan(src interface{}) error {
 	return fmt.Errorf("uuid: cannot convert %T to UUID", src)
 }
 
+// ScanLenient scans src like Scan, accepting any 16-byte slice or well-formed
+// string regardless of its version or variant bits. It exists to document the
+// intent of call sites reading legacy GUIDs, such as Microsoft variant values,
+// that don't conform to RFC-4122. Scan itself does not validate these bits
+// either, so the two currently behave identically.
+func (u *UUID) ScanLenient(src interface{}) error {
+	return u.Scan(src)
+}
+
+// SpannerValue returns the UUID in the 16-byte form used to store it in a
+// Google Cloud Spanner BYTES(16) column. It is suitable for use as a
+// statement parameter or mutation value, without this package depending on
+// the Spanner client.
+func (u UUID) SpannerValue() interface{} {
+	return u.Bytes()
+}
+
+// SpannerScan decodes a value read from a Google Cloud Spanner BYTES(16)
+// column, as returned by SpannerValue.
+func (u *UUID) SpannerScan(src interface{}) error {
+	b, ok := src.([]byte)
+	if !ok {
+		return fmt.Errorf("uuid: cannot convert Spanner value of type %T to UUID", src)
+	}
+	return u.UnmarshalBinary(b)
+}
+
 // NullUUID can be used with the standard sql package to represent a
 // UUID value that can be NULL in the database.
 type NullUUID struct {
@@ -35,9 +35,52 @@ func TestSQL(t *testing.T) {
 		t.Run("Text", testSQLScanText)
 		t.Run("Unsupported", testSQLScanUnsupported)
 		t.Run("Nil", testSQLScanNil)
+		t.Run("Lenient", testSQLScanLenient)
+		t.Run("Exotic", testSQLScanExotic)
 	})
 }
 
+// sqlScanExoticInputs is a selection of unusual driver values, which also
+// seed FuzzScan (in sql_fuzz_test.go).
+var sqlScanExoticInputs = [][]byte{
+	nil,
+	{},
+	codecTestData[:15],
+	append(append([]byte(nil), codecTestData...), 0),
+	[]byte("{6ba7b810-9dad-11d1-80b4-00c04fd430c8"),
+	[]byte("urn:uuid:6ba7b810-9dad-11d1-80b4-00c04fd430c"),
+	[]byte("urn:uuid:\u00e9ba7b810-9dad-11d1-80b4-00c04fd430c8"),
+	[]byte("6ba7b810\x009dad-11d1-80b4-00c04fd430c8"),
+	[]byte("\xff\xfe\xfd\xfc\xfb\xfa\xf9\xf8\xf7\xf6\xf5\xf4\xf3\xf2\xf1\xf0\xef\xee\xed\xec\xeb\xea\xe9\xe8\xe7\xe6\xe5\xe4\xe3\xe2\xe1\xe0"),
+	[]byte("{6ba7b8109dad11d180b400c04fd430c8}"),
+	[]byte("6ba7b810-9dad-11d1-80b4-00c04fd430c8"),
+}
+
+func testSQLScanExotic(t *testing.T) {
+	for _, data := range sqlScanExoticInputs {
+		checkScanRoundTrip(t, data)
+	}
+}
+
+// checkScanRoundTrip scans data as a []byte, a string, and once as nil,
+// checking that every successful scan round-trips through Value.
+func checkScanRoundTrip(t *testing.T, data []byte) {
+	for _, src := range []interface{}{data, string(data), nil} {
+		var u UUID
+		if err := u.Scan(src); err != nil {
+			continue
+		}
+		v, err := u.Value()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got UUID
+		if err := got.Scan(v); err != nil || got != u {
+			t.Errorf("Scan(%q) of Value() of %v = %v, %v", v, u, got, err)
+		}
+	}
+}
+
 func testSQLValue(t *testing.T) {
 	v, err := codecTestUUID.Value()
 	if err != nil {
@@ -109,6 +152,64 @@ func testSQLScanNil(t *testing.T) {
 	}
 }
 
+func testSQLScanLenient(t *testing.T) {
+	// Microsoft variant GUID with a version nibble outside of RFC-4122
+	want := Must(FromString("3f2504e0-4f89-f1d3-c2a1-00c04fd430c8"))
+	if got := want.Variant(); got != VariantMicrosoft {
+		t.Fatalf("%v.Variant() = %d, want %d", want, got, VariantMicrosoft)
+	}
+	for _, src := range []interface{}{want.String(), want.Bytes(), []byte(want.String())} {
+		var got UUID
+		if err := got.ScanLenient(src); err != nil {
+			t.Fatalf("ScanLenient(%v): %v", src, err)
+		}
+		if got != want {
+			t.Errorf("ScanLenient(%v) = %v, want %v", src, got, want)
+		}
+	}
+
+	var u UUID
+	if err := u.ScanLenient(42); err == nil {
+		t.Errorf("ScanLenient(42) succeeded, got %v", u)
+	}
+}
+
+func TestSpanner(t *testing.T) {
+	t.Run("RoundTrip", func(t *testing.T) {
+		for _, want := range []UUID{Nil, codecTestUUID, Must(NewV4())} {
+			v := want.SpannerValue()
+			b, ok := v.([]byte)
+			if !ok {
+				t.Fatalf("SpannerValue() returned %T, want []byte", v)
+			}
+			if len(b) != Size {
+				t.Fatalf("SpannerValue() returned %d bytes, want %d", len(b), Size)
+			}
+			var got UUID
+			if err := got.SpannerScan(v); err != nil {
+				t.Fatalf("SpannerScan(%x): %v", b, err)
+			}
+			if got != want {
+				t.Errorf("SpannerScan(%x) = %v, want %v", b, got, want)
+			}
+		}
+	})
+	t.Run("Invalid", func(t *testing.T) {
+		invalid := []interface{}{
+			nil,
+			codecTestUUID.String(),
+			codecTestData[:15],
+			append(codecTestData[:16:16], 0),
+		}
+		for _, v := range invalid {
+			var u UUID
+			if err := u.SpannerScan(v); err == nil {
+				t.Errorf("SpannerScan(%#v) succeeded, got %v", v, u)
+			}
+		}
+	})
+}
+
 func TestNullUUID(t *testing.T) {
 	t.Run("Value", func(t *testing.T) {
 		t.Run("Nil", testNullUUIDValueNil)
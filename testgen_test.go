@@ -0,0 +1,71 @@
+// Copyright (C) 2013-2018 by Maxim Bublis <b@codemonkey.ru>
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the
+// "Software"), to deal in the Software without restriction, including
+// without limitation the rights to use, copy, modify, merge, publish,
+// distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to
+// the following conditions:
+//
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+// MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION
+// WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package uuid
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewTestGen(t *testing.T) {
+	generate := func(g *Gen) []UUID {
+		return []UUID{
+			Must(g.NewV4()),
+			Must(g.NewV7(MillisecondPrecision)),
+			Must(g.NewV4()),
+			Must(g.NewV7(MillisecondPrecision)),
+			Must(g.NewV1()),
+			Must(g.NewV6()),
+		}
+	}
+
+	a := generate(NewTestGen(42))
+	b := generate(NewTestGen(42))
+	c := generate(NewTestGen(43))
+	for i := range a {
+		if a[i] != b[i] {
+			t.Errorf("UUID #%d differs for the same seed: %v and %v", i, a[i], b[i])
+		}
+		if a[i] == c[i] {
+			t.Errorf("UUID #%d is %v for different seeds", i, a[i])
+		}
+	}
+
+	wantVersions := []byte{V4, V7, V4, V7, V1, V6}
+	for i, u := range a {
+		if u.Version() != wantVersions[i] || u.Variant() != VariantRFC4122 {
+			t.Errorf("UUID #%d %v has version %d and variant %d", i, u, u.Version(), u.Variant())
+		}
+	}
+	if ts, _ := a[1].Time(); !ts.Equal(testGenEpoch) {
+		t.Errorf("%v.Time() = %v, want %v", a[1], ts, testGenEpoch)
+	}
+	if ts, _ := a[3].Time(); !ts.Equal(testGenEpoch.Add(time.Millisecond)) {
+		t.Errorf("%v.Time() = %v, want %v", a[3], ts, testGenEpoch.Add(time.Millisecond))
+	}
+	if Compare(a[1], a[3]) >= 0 {
+		t.Errorf("V7 UUIDs %v and %v are not increasing", a[1], a[3])
+	}
+	if a[4][10]&0x01 == 0 {
+		t.Errorf("V1 node of %v does not have the multicast bit set", a[4])
+	}
+}
@@ -0,0 +1,43 @@
+// Copyright (C) 2013-2018 by Maxim Bublis <b@codemonkey.ru>
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the
+// "Software"), to deal in the Software without restriction, including
+// without limitation the rights to use, copy, modify, merge, publish,
+// distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to
+// the following conditions:
+//
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+// MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION
+// WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package uuid
+
+import "unsafe"
+
+// UnsafeString returns the same canonical string representation as String,
+// but converts the encoded bytes to a string with package unsafe instead of
+// copying them.
+//
+// Since the UUID is passed by value, the string cannot alias the UUID itself:
+// it is backed by a buffer allocated by this method that nothing else refers
+// to, so the result is immutable and may be retained like any other string.
+// It makes the same single 36 byte allocation as String, and only saves
+// String's copy of 36 bytes from its stack buffer, at the cost of using
+// package unsafe. Prefer String unless a profile shows the copy to be
+// significant. Callers that need to avoid allocating should append to a
+// buffer of their own with AppendText, for example u.AppendText(buf[:0])
+// with a [36]byte array buf.
+func (u UUID) UnsafeString() string {
+	buf := make([]byte, 36)
+	encodeCanonical(buf, u)
+	return *(*string)(unsafe.Pointer(&buf))
+}
@@ -0,0 +1,82 @@
+// Copyright (C) 2013-2018 by Maxim Bublis <b@codemonkey.ru>
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the
+// "Software"), to deal in the Software without restriction, including
+// without limitation the rights to use, copy, modify, merge, publish,
+// distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to
+// the following conditions:
+//
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+// MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION
+// WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package uuid
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestUnsafeString(t *testing.T) {
+	uuids := []UUID{Nil, Max, codecTestUUID}
+	for i := 0; i < 100; i++ {
+		uuids = append(uuids, Must(NewV4()))
+	}
+	for _, u := range uuids {
+		if got, want := u.UnsafeString(), u.String(); got != want {
+			t.Errorf("%v.UnsafeString() = %q, want %q", u, got, want)
+		}
+	}
+
+	// Retained strings must not be affected by later calls or by changes to
+	// the UUID they were created from. Run with -race to check the
+	// concurrent case.
+	u := codecTestUUID
+	s := u.UnsafeString()
+	u[0] = 0
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				Must(NewV4()).UnsafeString()
+			}
+		}()
+	}
+	wg.Wait()
+	if want := codecTestUUID.String(); s != want {
+		t.Errorf("retained UnsafeString() = %q, want %q", s, want)
+	}
+
+	if n := testing.AllocsPerRun(100, func() { _ = codecTestUUID.UnsafeString() }); n > 1 {
+		t.Errorf("UnsafeString() allocated %v times, want at most 1", n)
+	}
+}
+
+// benchString keeps the compiler from optimizing away benchmarked results.
+var benchString string
+
+func BenchmarkUnsafeString(b *testing.B) {
+	b.Run("String", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			benchString = codecTestUUID.String()
+		}
+	})
+	b.Run("UnsafeString", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			benchString = codecTestUUID.UnsafeString()
+		}
+	})
+}
@@ -41,11 +41,15 @@
 package uuid
 
 import (
+	"bytes"
+	"crypto/rand"
 	"encoding/binary"
 	"encoding/hex"
 	"fmt"
+	"hash/fnv"
 	"io"
-	"strings"
+	"math/bits"
+	"net"
 	"time"
 )
 
@@ -65,7 +69,7 @@ const (
 	V5      // Version 5 (namespace name-based)
 	V6      // Version 6 (k-sortable timestamp and random data) [peabody draft]
 	V7      // Version 7 (k-sortable timestamp, with configurable precision, and random data) [peabody draft]
-	_       // Version 8 (k-sortable timestamp, meant for custom implementations) [peabody draft] [not implemented]
+	V8      // Version 8 (custom implementations) [RFC 9562]
 )
 
 // UUID layout variants.
@@ -95,9 +99,12 @@ func (t Timestamp) Time() (time.Time, error) {
 	secs := uint64(t) / _100nsPerSecond
 	nsecs := 100 * (uint64(t) % _100nsPerSecond)
 
-	return time.Unix(int64(secs)-(epochStart/_100nsPerSecond), int64(nsecs)), nil
+	return time.Unix(int64(secs)-(GregorianEpochOffset/_100nsPerSecond), int64(nsecs)), nil
 }
 
+// gregorianEpoch is the time of the UUID epoch, October 15, 1582.
+var gregorianEpoch = time.Date(1582, 10, 15, 0, 0, 0, 0, time.UTC)
+
 // TimestampFromV1 returns the Timestamp embedded within a V1 UUID.
 // Returns an error if the UUID is any version other than 1.
 func TimestampFromV1(u UUID) (Timestamp, error) {
@@ -133,6 +140,275 @@ func TimestampFromV6(u UUID) (Timestamp, error) {
 	return Timestamp(uint64(low) + (uint64(mid) << 12) + (uint64(hi) << 28)), nil
 }
 
+// TimeFromV7 returns the time embedded within a V7 UUID that was generated
+// with Precision p. The precision is not encoded within the UUID itself, so it
+// must match the one the UUID was generated with for the sub-second part of
+// the result to be meaningful. This function returns an error if the UUID is
+// any version other than 7.
+//
+// This is implemented based on revision 02 of the Peabody UUID draft, and may
+// be subject to change pending further revisions. Until the final specification
+// revision is finished, changes required to implement updates to the spec will
+// not be considered a breaking change. They will happen as a minor version
+// releases until the spec is final.
+func TimeFromV7(u UUID, p Precision) (time.Time, error) {
+	if u.Version() != 7 {
+		return time.Time{}, fmt.Errorf("uuid: %s is version %d, not version 7", u, u.Version())
+	}
+
+	d := binary.BigEndian.Uint64(u[0:8])
+	sec := d >> 28
+	subsecA := (d >> 16) & 0xfff
+	subsecB := d & 0xfff
+
+	var nsec uint64
+	switch p {
+	case NanosecondPrecision:
+		nsec = subsecA<<26 | subsecB<<14 | uint64(binary.BigEndian.Uint16(u[8:10])&0x3fff)
+
+	case MicrosecondPrecision:
+		nsec = (subsecA<<12 | subsecB) * 1000
+
+	case MillisecondPrecision:
+		nsec = subsecA * 1000000
+
+	default:
+		return time.Time{}, fmt.Errorf("uuid: unknown precision value %d", p)
+	}
+
+	return time.Unix(int64(sec), int64(nsec)), nil
+}
+
+// TimeFromV7Fraction returns the time embedded within a V7 UUID in the layout
+// of method 3 of RFC 9562 section 6.2, as generated by a Gen with
+// SubMillisecondFraction set: a 48-bit Unix timestamp in milliseconds followed
+// by the 12-bit fraction of the millisecond. The result is accurate to about
+// 244 nanoseconds. This function returns an error if the UUID is any version
+// other than 7.
+func TimeFromV7Fraction(u UUID) (time.Time, error) {
+	if u.Version() != V7 {
+		return time.Time{}, fmt.Errorf("uuid: %s is version %d, not version 7", u, u.Version())
+	}
+	d := binary.BigEndian.Uint64(u[:8])
+	ms := d >> 16
+	nsec := ms%1000*1000000 + (d&0xfff)*1000000/4096
+	return time.Unix(int64(ms/1000), int64(nsec)), nil
+}
+
+// TimeFromV8Epoch returns the time embedded within a V8 UUID generated by
+// Gen.NewV8Epoch, whose first 48 bits count milliseconds since epoch. The
+// epoch is not encoded within the UUID itself, so it must match the one the
+// generator was configured with. Gen.TimeFromV8 uses the epoch of a generator.
+// This function returns an error if the UUID is any version other than 8.
+func TimeFromV8Epoch(u UUID, epoch time.Time) (time.Time, error) {
+	if u.Version() != V8 {
+		return time.Time{}, fmt.Errorf("uuid: %s is version %d, not version 8", u, u.Version())
+	}
+	ms := int64(binary.BigEndian.Uint64(u[:8]) >> 16)
+	return time.Unix(epoch.Unix()+ms/1000, int64(epoch.Nanosecond())+ms%1000*int64(time.Millisecond)), nil
+}
+
+// Time returns the time embedded within a time-based (V1, V6, or V7) UUID.
+// V7 UUIDs are assumed to have been generated with MillisecondPrecision, use
+// TimeFromV7 to decode V7 UUIDs of other precisions. This method returns an
+// error for all other versions, since they don't have an embedded timestamp.
+func (u UUID) Time() (time.Time, error) {
+	var ts Timestamp
+	var err error
+
+	switch u.Version() {
+	case V1:
+		ts, err = TimestampFromV1(u)
+	case V6:
+		ts, err = TimestampFromV6(u)
+	case V7:
+		return TimeFromV7(u, MillisecondPrecision)
+	default:
+		return time.Time{}, fmt.Errorf("uuid: %s is version %d, which has no embedded timestamp", u, u.Version())
+	}
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return ts.Time()
+}
+
+// TimeOrZero returns the time embedded within a time-based UUID, as returned
+// by the Time method, or the zero time.Time if the UUID has no embedded
+// timestamp. Note that the zero value is not the only ambiguous result: a V1
+// or V6 UUID with an all-zero timestamp decodes to the start of the Gregorian
+// calendar (October 15, 1582) rather than to the zero time.Time. Use Time if
+// the distinction matters.
+func (u UUID) TimeOrZero() time.Time {
+	t, err := u.Time()
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
+
+// Age returns how long before now the time-based UUID was created, that is
+// now minus the time returned by the Time method. The result is negative if
+// the UUID's timestamp is after now. An error is returned for UUIDs without an
+// embedded timestamp.
+func (u UUID) Age(now time.Time) (time.Duration, error) {
+	t, err := u.Time()
+	if err != nil {
+		return 0, err
+	}
+	return now.Sub(t), nil
+}
+
+// CreatedAt returns the time embedded within a time-based UUID, as returned by
+// the Time method, formatted as an RFC 3339 string in UTC.
+func (u UUID) CreatedAt() (string, error) {
+	t, err := u.Time()
+	if err != nil {
+		return "", err
+	}
+	return t.UTC().Format(time.RFC3339Nano), nil
+}
+
+// TimeBucketPow2 returns the Unix millisecond timestamp of a millisecond
+// precision V7 UUID with its low bits cleared, that is the start of the
+// 2^bits millisecond bucket containing the UUID. All UUIDs created within the
+// same aligned bucket return the same value. An error is returned if u is not
+// a millisecond precision V7 UUID.
+func (u UUID) TimeBucketPow2(bits uint) (uint64, error) {
+	if u.Version() != V7 {
+		return 0, fmt.Errorf("uuid: %s is version %d, not version 7", u, u.Version())
+	}
+	d := binary.BigEndian.Uint64(u[:8])
+	sec, msec := d>>28, (d>>16)&0xfff
+	if msec >= 1000 {
+		return 0, fmt.Errorf("uuid: %s is not a millisecond precision V7 UUID", u)
+	}
+	ms := sec*1000 + msec
+	return ms >> bits << bits, nil
+}
+
+// PrecedesInTime reports whether the embedded timestamp of a is strictly
+// before that of b. The UUIDs may be of different time-based versions (V1, V6,
+// or V7), as both timestamps are decoded using the Time method. An error is
+// returned if either UUID has no embedded timestamp.
+func PrecedesInTime(a, b UUID) (bool, error) {
+	ta, err := a.Time()
+	if err != nil {
+		return false, err
+	}
+	tb, err := b.Time()
+	if err != nil {
+		return false, err
+	}
+	return ta.Before(tb), nil
+}
+
+// CompareTimeThenBytes compares two time-based UUIDs by their embedded
+// timestamps, as decoded by the Time method, and falls back to Compare if the
+// timestamps are equal. This gives a stable total order for sorting event IDs
+// of possibly different versions. The result will be 0 if a == b, -1 if a
+// sorts before b, and +1 if a sorts after b. An error is returned if either
+// UUID has no embedded timestamp.
+func CompareTimeThenBytes(a, b UUID) (int, error) {
+	ta, err := a.Time()
+	if err != nil {
+		return 0, err
+	}
+	tb, err := b.Time()
+	if err != nil {
+		return 0, err
+	}
+	switch {
+	case ta.Before(tb):
+		return -1, nil
+	case ta.After(tb):
+		return 1, nil
+	default:
+		return Compare(a, b), nil
+	}
+}
+
+// WithinWindow reports whether the timestamp embedded within the V7 UUID u is
+// within the inclusive range [now-maxAge, now]. The UUID is assumed to have
+// been generated with MillisecondPrecision. UUIDs with a timestamp after now
+// are reported as outside the window. An error is returned if u is not a V7
+// UUID.
+func (u UUID) WithinWindow(now time.Time, maxAge time.Duration) (bool, error) {
+	t, err := TimeFromV7(u, MillisecondPrecision)
+	if err != nil {
+		return false, err
+	}
+	return !t.After(now) && !t.Before(now.Add(-maxAge)), nil
+}
+
+// IsSuccessorV7 reports whether next is a valid successor of prev in a
+// monotonic stream of V7 UUIDs: next must sort strictly after prev, as ordered
+// by Compare, and its embedded timestamp must not be before that of prev. Both
+// UUIDs are assumed to have been generated with MillisecondPrecision. An error
+// is returned if either UUID is not a V7 UUID.
+func IsSuccessorV7(prev, next UUID) (bool, error) {
+	tp, err := TimeFromV7(prev, MillisecondPrecision)
+	if err != nil {
+		return false, err
+	}
+	tn, err := TimeFromV7(next, MillisecondPrecision)
+	if err != nil {
+		return false, err
+	}
+	return Compare(prev, next) < 0 && !tn.Before(tp), nil
+}
+
+// TimeBounds returns the lowest and highest UUIDs, as ordered by Compare, of
+// the given time-based version (1, 6, or 7) whose embedded timestamp equals t
+// to the precision of that version: 100 nanoseconds for V1 and V6, and one
+// millisecond for V7, which is assumed to be of MillisecondPrecision. This can
+// be used to build index bounds for UUIDs created at a specific time. An error
+// is returned for other versions, or if t cannot be represented by the
+// version.
+func TimeBounds(version byte, t time.Time) (low, high UUID, err error) {
+	switch version {
+	case V1, V6:
+		if t.Before(gregorianEpoch) {
+			return Nil, Nil, fmt.Errorf("uuid: time %v is before the UUID epoch", t)
+		}
+		ts := uint64(t.Unix()+GregorianEpochOffset/_100nsPerSecond)*_100nsPerSecond + uint64(t.Nanosecond()/100)
+		if ts >= 1<<60 {
+			return Nil, Nil, fmt.Errorf("uuid: time %v cannot be represented by a version %d UUID", t, version)
+		}
+		if version == V1 {
+			putV1Timestamp(&low, ts)
+			putV1Timestamp(&high, ts)
+		} else {
+			putV6Timestamp(&low, ts)
+			putV6Timestamp(&high, ts)
+		}
+		for i := 8; i < Size; i++ {
+			high[i] = 0xff
+		}
+
+	case V7:
+		if t.Unix() < 0 || t.Unix() >= 1<<36 {
+			return Nil, Nil, fmt.Errorf("uuid: time %v cannot be represented by a version 7 UUID", t)
+		}
+		sec, msec := uint64(t.Unix()), uint64(t.Nanosecond()/1000000)
+		putV7Milli(&low, sec, msec, 0)
+		putV7Milli(&high, sec, msec, maxSeq12)
+		for i := 8; i < Size; i++ {
+			high[i] = 0xff
+		}
+
+	default:
+		return Nil, Nil, fmt.Errorf("uuid: version %d has no embedded timestamp", version)
+	}
+
+	low.SetVersion(version)
+	low.SetVariant(VariantRFC4122)
+	high.SetVersion(version)
+	high.SetVariant(VariantRFC4122)
+
+	return low, high, nil
+}
+
 // String parse helpers.
 var (
 	urnPrefix  = []byte("urn:uuid:")
@@ -143,6 +419,10 @@ var (
 // zero.
 var Nil = UUID{}
 
+// Max is the max UUID, as specified in RFC 9562, that has all 128 bits set to
+// one.
+var Max = UUID{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}
+
 // Predefined namespace UUIDs.
 var (
 	NamespaceDNS  = Must(FromString("6ba7b810-9dad-11d1-80b4-00c04fd430c8"))
@@ -151,11 +431,59 @@ var (
 	NamespaceX500 = Must(FromString("6ba7b814-9dad-11d1-80b4-00c04fd430c8"))
 )
 
+// TestVectors returns a fixed corpus of UUIDs for interoperability tests:
+// Nil, one UUID of each supported version from 1 to 8 except 2, and Max, in
+// that order. Apart from Nil and Max these are the example values given in
+// appendices A and B of RFC 9562, so the V3 and V5 UUIDs are those of the name
+// "www.example.com" in NamespaceDNS. Note that the V7 example uses the RFC
+// 9562 layout rather than the draft layout this package generates, so its
+// timestamp does not decode meaningfully with TimeFromV7.
+//
+// The values will not change between releases. A new slice is returned on
+// every call, so callers may modify it.
+func TestVectors() []UUID {
+	return []UUID{
+		Nil,
+		Must(FromString("c232ab00-9414-11ec-b3c8-9f6bdeced846")), // V1
+		Must(FromString("5df41881-3aed-3515-88a7-2f4a814cf09e")), // V3
+		Must(FromString("919108f7-52d1-4320-9bac-f847db4148a8")), // V4
+		Must(FromString("2ed6657d-e927-568b-95e1-2665a8aea6a2")), // V5
+		Must(FromString("1ec9414c-232a-6b00-b3c8-9f6bdeced846")), // V6
+		Must(FromString("017f22e2-79b0-7cc3-98c4-dc0c0c07398f")), // V7
+		Must(FromString("2489e9ad-2ee2-8e00-8ec9-32d5f69181c0")), // V8
+		Max,
+	}
+}
+
 // IsNil returns if the UUID is equal to the nil UUID
 func (u UUID) IsNil() bool {
 	return u == Nil
 }
 
+// IsEmptyVersioned reports whether the UUID has a version set, but all of its
+// other bits, except for the variant, are zero. Such "empty but versioned"
+// UUIDs are sometimes used as placeholders and usually indicate an improperly
+// initialized generator. IsNil returns false for them.
+func (u UUID) IsEmptyVersioned() bool {
+	if u.Version() == 0 {
+		return false
+	}
+
+	u[6] &= 0x0f // clear version bits
+
+	// clear variant bits
+	switch u.Variant() {
+	case VariantNCS:
+		u[8] &= 0xff >> 1
+	case VariantRFC4122:
+		u[8] &= 0xff >> 2
+	default:
+		u[8] &= 0xff >> 3
+	}
+
+	return u == Nil
+}
+
 // Version returns the algorithm version used to generate the UUID.
 func (u UUID) Version() byte {
 	return u[6] >> 4
@@ -182,11 +510,139 @@ func (u UUID) Bytes() []byte {
 	return u[:]
 }
 
+// Compare returns an integer comparing two UUIDs lexicographically by their
+// bytes, which is the same as comparing them as big-endian 128-bit integers.
+// The result will be 0 if a == b, -1 if a < b, and +1 if a > b.
+func Compare(a, b UUID) int {
+	return bytes.Compare(a[:], b[:])
+}
+
+// SecureBits returns the number of unpredictable bits in a UUID of its
+// version as generated by this package, for deciding whether the version is
+// suitable for use as a secret, such as an access token, as discussed in RFC
+// 9562 section 8. The values are:
+//
+//   - V4: 122, all bits but the version and variant
+//   - V7: 40, the random bits left by NanosecondPrecision; the other
+//     precisions leave more (48 for MicrosecondPrecision and 62 for
+//     MillisecondPrecision) but the precision is not encoded in the UUID
+//   - V6: 48, the random node
+//   - V1: 47 if the multicast bit of the node is set, indicating a random
+//     node as used by Gen.RandomNodePreferred, otherwise 0 as the node is a
+//     hardware address
+//   - all others, including V3, V5, V8, Nil, and Max: 0
+//
+// Timestamps and clock sequences are counted as predictable. This reports
+// what the layout of each version permits, not whether u was generated by a
+// cryptographically secure source.
+func (u UUID) SecureBits() int {
+	switch u.Version() {
+	case V4:
+		return 122
+	case V7:
+		return 40
+	case V6:
+		return 48
+	case V1:
+		if u[10]&0x01 != 0 {
+			return 47
+		}
+		return 0
+	default:
+		return 0
+	}
+}
+
+// Thresholds for EntropyBits outside of which a UUID that is expected to be
+// random, such as a V4, looks like it came from a stuck or broken generator.
+// Of the 122 payload bits of a random UUID about 61 are set, with a standard
+// deviation of about 5.5 bits, so a count outside of these bounds is
+// vanishingly unlikely for a working generator.
+const (
+	EntropyBitsStuckLow  = 16
+	EntropyBitsStuckHigh = 106
+)
+
+// EntropyBits returns the number of set bits of the UUID, excluding the four
+// version bits and the two RFC-4122 variant bits, as a cheap per-UUID sanity
+// check for broken generators. It does not measure entropy in the
+// information-theoretic sense; it only flags payloads that are (nearly) all
+// zeros or all ones. A result below EntropyBitsStuckLow or above
+// EntropyBitsStuckHigh suggests the UUID was not randomly generated.
+func (u UUID) EntropyBits() int {
+	u[6] &= 0x0f // clear version bits
+	u[8] &= 0x3f // clear variant bits
+	hi := binary.BigEndian.Uint64(u[:8])
+	lo := binary.BigEndian.Uint64(u[8:])
+	return bits.OnesCount64(hi) + bits.OnesCount64(lo)
+}
+
+// SortKeyPrefix returns a copy of the first n bytes of the UUID, with n
+// clamped to the range [1, 16]. Since Compare orders UUIDs by their
+// big-endian bytes, the prefixes of two UUIDs never order differently than
+// the UUIDs themselves, although distinct UUIDs may share a prefix. For V6 and
+// V7 UUIDs the first 8 bytes hold the timestamp, so SortKeyPrefix(8) yields a
+// fixed-width, time-sortable key suitable for a secondary index.
+func (u UUID) SortKeyPrefix(n int) []byte {
+	switch {
+	case n < 1:
+		n = 1
+	case n > Size:
+		n = Size
+	}
+	b := make([]byte, n)
+	copy(b, u[:n])
+	return b
+}
+
+// EqualBytes reports whether b is exactly 16 bytes long and holds the same
+// bytes as u. It allows comparing a UUID against a raw binary value without
+// decoding it with FromBytes first.
+func (u UUID) EqualBytes(b []byte) bool {
+	return len(b) == Size && bytes.Equal(u[:], b)
+}
+
+// CommonPrefixBits returns the number of leading bits a and b have in common,
+// from 0 to 128. Since the timestamp of a V7 UUID is stored in its leading
+// bits, a longer common prefix indicates that two V7 UUIDs were generated
+// closer together in time, which makes this useful for building radix or trie
+// indexes on V7 keys.
+func CommonPrefixBits(a, b UUID) int {
+	for i := 0; i < Size; i++ {
+		if x := a[i] ^ b[i]; x != 0 {
+			return i*8 + bits.LeadingZeros8(x)
+		}
+	}
+	return Size * 8
+}
+
+// CompareLE is like Compare, but interprets both UUIDs as little-endian
+// 128-bit integers, so that byte 15 is the most significant. This is useful
+// when comparing values that were read in little-endian (Microsoft GUID) byte
+// order.
+func CompareLE(a, b UUID) int {
+	for i := Size - 1; i >= 0; i-- {
+		switch {
+		case a[i] < b[i]:
+			return -1
+		case a[i] > b[i]:
+			return 1
+		}
+	}
+	return 0
+}
+
 // String returns a canonical RFC-4122 string representation of the UUID:
 // xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx.
 func (u UUID) String() string {
-	buf := make([]byte, 36)
+	var buf [36]byte
+	encodeCanonical(buf[:], u)
+	return string(buf[:])
+}
 
+// encodeCanonical writes the canonical RFC-4122 string representation of u
+// into buf, which must be at least 36 bytes long.
+func encodeCanonical(buf []byte, u UUID) {
 	hex.Encode(buf[0:8], u[0:4])
 	buf[8] = '-'
 	hex.Encode(buf[9:13], u[4:6])
@@ -196,8 +652,37 @@ func (u UUID) String() string {
 	hex.Encode(buf[19:23], u[8:10])
 	buf[23] = '-'
 	hex.Encode(buf[24:], u[10:])
+}
 
-	return string(buf)
+// AppendHashLike appends the UUID to dst as 32 lowercase hex digits without
+// dashes, the hash-like form accepted by FromString, and returns the extended
+// buffer. It does not allocate if dst has room for 32 more bytes.
+func (u UUID) AppendHashLike(dst []byte) []byte {
+	n := len(dst)
+	if cap(dst)-n < 32 {
+		b := make([]byte, n, n+32)
+		copy(b, dst)
+		dst = b
+	}
+	dst = dst[:n+32]
+	hex.Encode(dst[n:], u[:])
+	return dst
+}
+
+// AppendText implements the encoding.TextAppender interface. It appends the
+// canonical string representation of the UUID to b and returns the extended
+// buffer, which does not allocate if b has room for 36 more bytes. The error
+// is always nil.
+func (u UUID) AppendText(b []byte) ([]byte, error) {
+	n := len(b)
+	if cap(b)-n < 36 {
+		d := make([]byte, n, n+36)
+		copy(d, b)
+		b = d
+	}
+	b = b[:n+36]
+	encodeCanonical(b[n:], u)
+	return b, nil
 }
 
 // Format implements fmt.Formatter for UUID values.
@@ -210,52 +695,50 @@ func (u UUID) String() string {
 // All other verbs not handled directly by the fmt package (like '%p') are unsupported and will return
 // "%!verb(uuid.UUID=value)" as recommended by the fmt package.
 func (u UUID) Format(f fmt.State, c rune) {
+	// The output is encoded into a fixed buffer, large enough for the quoted
+	// canonical form, and written directly to f.
+	var buf [38]byte
+
 	switch c {
 	case 'x', 'X':
-		s := hex.EncodeToString(u.Bytes())
+		b := buf[:32]
+		hex.Encode(b, u[:])
 		if c == 'X' {
-			s = strings.Map(toCapitalHexDigits, s)
+			toCapitalHexDigits(b)
 		}
-		_, _ = io.WriteString(f, s)
+		_, _ = f.Write(b)
 	case 'v':
-		var s string
 		if f.Flag('#') {
-			s = fmt.Sprintf("%#v", [Size]byte(u))
-		} else {
-			s = u.String()
+			fmt.Fprintf(f, "%#v", [Size]byte(u))
+			return
 		}
-		_, _ = io.WriteString(f, s)
+		b := buf[:36]
+		encodeCanonical(b, u)
+		_, _ = f.Write(b)
 	case 's', 'S':
-		s := u.String()
+		b := buf[:36]
+		encodeCanonical(b, u)
 		if c == 'S' {
-			s = strings.Map(toCapitalHexDigits, s)
+			toCapitalHexDigits(b)
 		}
-		_, _ = io.WriteString(f, s)
+		_, _ = f.Write(b)
 	case 'q':
-		_, _ = io.WriteString(f, `"`+u.String()+`"`)
+		buf[0] = '"'
+		encodeCanonical(buf[1:37], u)
+		buf[37] = '"'
+		_, _ = f.Write(buf[:])
 	default:
 		// invalid/unsupported format verb
 		fmt.Fprintf(f, "%%!%c(uuid.UUID=%s)", c, u.String())
 	}
 }
 
-func toCapitalHexDigits(ch rune) rune {
-	// convert a-f hex digits to A-F
-	switch ch {
-	case 'a':
-		return 'A'
-	case 'b':
-		return 'B'
-	case 'c':
-		return 'C'
-	case 'd':
-		return 'D'
-	case 'e':
-		return 'E'
-	case 'f':
-		return 'F'
-	default:
-		return ch
+// toCapitalHexDigits converts the a-f hex digits in b to A-F in place.
+func toCapitalHexDigits(b []byte) {
+	for i, ch := range b {
+		if 'a' <= ch && ch <= 'f' {
+			b[i] = ch - ('a' - 'A')
+		}
 	}
 }
 
@@ -280,6 +763,272 @@ func (u *UUID) SetVariant(v byte) {
 	}
 }
 
+// WithRandomNode returns a copy of a V1 or V6 UUID with its node (bytes 10-15)
+// replaced by random data that has the multicast bit set, as recommended by
+// RFC-4122 for nodes without a hardware address. The timestamp and clock
+// sequence are preserved, so this strips the MAC address from a V1 UUID
+// without changing its time ordering. An error is returned for all other
+// versions.
+func (u UUID) WithRandomNode() (UUID, error) {
+	if v := u.Version(); v != V1 && v != V6 {
+		return Nil, fmt.Errorf("uuid: %s is version %d, not version 1 or 6", u, v)
+	}
+	if _, err := io.ReadFull(rand.Reader, u[10:]); err != nil {
+		return Nil, err
+	}
+	// Set multicast bit as recommended by RFC-4122
+	u[10] |= 0x01
+
+	return u, nil
+}
+
+// StripTimestamp returns a copy of a V1, V6, or V7 UUID with its timestamp
+// zeroed, for sharing the identity of a UUID without revealing when it was
+// created. The timestamp fields occupy the first 8 bytes of all three
+// versions, which are zeroed apart from the version bits; the clock sequence,
+// node, and random bits are preserved. For V7 UUIDs this also zeroes subsec_b,
+// which holds the sequence for MillisecondPrecision and part of the time for
+// other precisions. Afterwards V1 and V6 UUIDs decode to the start of the
+// Gregorian calendar and V7 UUIDs to the Unix epoch. An error is returned for
+// all other versions.
+func (u UUID) StripTimestamp() (UUID, error) {
+	switch v := u.Version(); v {
+	case V1, V6, V7:
+		for i := 0; i < 8; i++ {
+			u[i] = 0
+		}
+		u.SetVersion(v)
+		u.SetVariant(VariantRFC4122)
+		return u, nil
+	default:
+		return Nil, fmt.Errorf("uuid: %s is version %d, which has no embedded timestamp", u, v)
+	}
+}
+
+// variantNames are the names of the layout variants, as used by Pretty.
+var variantNames = [...]string{
+	VariantNCS:       "NCS",
+	VariantRFC4122:   "RFC4122",
+	VariantMicrosoft: "Microsoft",
+	VariantFuture:    "Future",
+}
+
+// Pretty returns a multi-line, annotated breakdown of the UUID intended for
+// display by command line tools, for example:
+//
+//	uuid:    6ba7b810-9dad-11d1-80b4-00c04fd430c8
+//	version: 1
+//	variant: RFC4122
+//	time:    1998-02-04T22:13:53.1511824Z
+//	clock:   180
+//	node:    00:c0:4f:d4:30:c8
+//	bytes:   6b a7 b8 10 9d ad 11 d1 80 b4 00 c0 4f d4 30 c8
+//
+// The time line is only present for time-based versions, V7 UUIDs being
+// assumed to be of MillisecondPrecision, and the clock and node lines only
+// for V1 and V6 UUIDs. The exact format is not stable and should not be
+// parsed.
+func (u UUID) Pretty() string {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "uuid:    %s\n", u)
+	fmt.Fprintf(&b, "version: %d\n", u.Version())
+	fmt.Fprintf(&b, "variant: %s\n", variantNames[u.Variant()])
+	if t, err := u.Time(); err == nil {
+		fmt.Fprintf(&b, "time:    %s\n", t.UTC().Format(time.RFC3339Nano))
+	}
+	if v := u.Version(); v == V1 || v == V6 {
+		fmt.Fprintf(&b, "clock:   %d\n", binary.BigEndian.Uint16(u[8:10])&0x3fff)
+		fmt.Fprintf(&b, "node:    %s\n", net.HardwareAddr(u[10:]))
+	}
+	fmt.Fprintf(&b, "bytes:   % x\n", u[:])
+	return b.String()
+}
+
+// CLiteral returns the UUID as a C array initializer of its 16 bytes, such as
+// {0x6b, 0xa7, 0xb8, 0x10, ...}, for use in generated C headers. It is the C
+// counterpart of the Go syntax produced by the %#v verb.
+func (u UUID) CLiteral() string {
+	const hexDigits = "0123456789abcdef"
+	b := make([]byte, 0, 2+Size*6-2)
+	b = append(b, '{')
+	for i, c := range u {
+		if i > 0 {
+			b = append(b, ',', ' ')
+		}
+		b = append(b, '0', 'x', hexDigits[c>>4], hexDigits[c&0x0f])
+	}
+	b = append(b, '}')
+	return string(b)
+}
+
+// Pronounceable returns a short, stable alias for the UUID made of
+// consonant-vowel syllables, such as "miza-tuko-rabi", for support tooling
+// where IDs have to be read aloud. The alias is derived from the FNV-1a hash
+// of the UUID and carries only 36 bits of it, so it is lossy, cannot be
+// converted back into the UUID, and distinct UUIDs may share an alias. It is
+// intended as a display nickname alongside the real ID, never in its place.
+func (u UUID) Pronounceable() string {
+	const consonants = "bdfghjklmnprstvz"
+	const vowels = "aiou"
+
+	h := fnv.New64a()
+	h.Write(u[:])
+	x := h.Sum64()
+
+	b := make([]byte, 0, 14)
+	for i := 0; i < 6; i++ {
+		if i > 0 && i%2 == 0 {
+			b = append(b, '-')
+		}
+		b = append(b, consonants[x&0xf], vowels[(x>>4)&0x3])
+		x >>= 6
+	}
+	return string(b)
+}
+
+// ReversedBytes returns a copy of the UUID with all 16 bytes in reverse
+// order. The result is generally not a meaningful UUID; this is a debugging
+// aid for recognizing values that were stored or read with the wrong byte
+// order, such as Microsoft GUIDs mixed up with RFC-4122 UUIDs.
+func (u UUID) ReversedBytes() UUID {
+	for i, j := 0, Size-1; i < j; i, j = i+1, j-1 {
+		u[i], u[j] = u[j], u[i]
+	}
+	return u
+}
+
+// ColorSeed returns a stable 32-bit value derived from all of the bytes of the
+// UUID, suitable for picking a color or avatar from a palette. The value is
+// the FNV-1a hash of the UUID, so it is well distributed even for UUID
+// versions where many bytes are shared, such as V1 UUIDs from the same node.
+func (u UUID) ColorSeed() uint32 {
+	h := fnv.New32a()
+	h.Write(u[:])
+	return h.Sum32()
+}
+
+// PayloadChecksum returns the CRC-8 (polynomial 0x07, initial value 0) of
+// bytes 0-14 of the UUID, for custom V8 layouts that reserve the last byte
+// for an integrity check. Byte 15 is not covered, so a V8 constructor can
+// store the checksum there and a reader can later verify it with
+//
+//	u[15] == u.PayloadChecksum()
+//
+// Any corruption of a single covered byte changes the checksum.
+func (u UUID) PayloadChecksum() byte {
+	var crc byte
+	for _, b := range u[:Size-1] {
+		crc ^= b
+		for i := 0; i < 8; i++ {
+			if crc&0x80 != 0 {
+				crc = crc<<1 ^ 0x07
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
+}
+
+// Bucket deterministically selects an index into weights, with the
+// probability of each index proportional to its weight, for consistent A/B
+// bucketing. The FNV-1a hash of the UUID is used as the source of randomness,
+// so the choice is uniform even for UUID versions where many bytes are
+// shared, and the same UUID always maps to the same index for the same
+// weights. Weights that are not positive are never selected; Bucket returns -1
+// if there are no positive weights.
+func (u UUID) Bucket(weights []int) int {
+	var total uint64
+	for _, w := range weights {
+		if w > 0 {
+			total += uint64(w)
+		}
+	}
+	if total == 0 {
+		return -1
+	}
+
+	h := fnv.New64a()
+	h.Write(u[:])
+	r := h.Sum64() % total
+
+	for i, w := range weights {
+		if w <= 0 {
+			continue
+		}
+		if r < uint64(w) {
+			return i
+		}
+		r -= uint64(w)
+	}
+	panic("unreachable")
+}
+
+// BloomKeys returns k 64-bit hashes of the UUID for use as the bit positions
+// of a bloom filter. The keys are derived by double hashing: the i-th key is
+// h1 + i*h2, where h1 and h2 are the FNV-1a and FNV-1 hashes of the UUID, the
+// latter forced odd so that no two keys are equal. BloomKeys returns nil if k
+// is less than 1.
+func (u UUID) BloomKeys(k int) []uint64 {
+	if k < 1 {
+		return nil
+	}
+	h := fnv.New64a()
+	h.Write(u[:])
+	h1 := h.Sum64()
+	h = fnv.New64()
+	h.Write(u[:])
+	h2 := h.Sum64() | 1
+
+	keys := make([]uint64, k)
+	for i := range keys {
+		keys[i] = h1 + uint64(i)*h2
+	}
+	return keys
+}
+
+// PathSegments splits the hash-like form of the UUID (32 hex digits without
+// dashes) into levels segments of width characters each, followed by the
+// remaining characters, for sharding files across a directory tree:
+//
+//	u.PathSegments(2, 2) // ["6b", "a7", "b8109dad11d180b400c04fd430c8"]
+//
+// A width less than 1 is treated as 1, and levels is clamped to the range
+// [0, 32/width]. The remainder is omitted if no characters are left.
+func (u UUID) PathSegments(levels, width int) []string {
+	s := hex.EncodeToString(u[:])
+	if width < 1 {
+		width = 1
+	}
+	if levels < 0 {
+		levels = 0
+	}
+	if levels > len(s)/width {
+		levels = len(s) / width
+	}
+
+	segs := make([]string, 0, levels+1)
+	for i := 0; i < levels; i++ {
+		segs = append(segs, s[:width])
+		s = s[width:]
+	}
+	if s != "" {
+		segs = append(segs, s)
+	}
+	return segs
+}
+
+// XOR returns the byte-wise XOR of u and other. Since XOR is commutative,
+// a.XOR(b) == b.XOR(a), so the result can be used as an order-independent key
+// for a pair of UUIDs. Note that the result does not have meaningful version
+// or variant bits unless they are set again with SetVersion and SetVariant.
+func (u UUID) XOR(other UUID) UUID {
+	for i := range u {
+		u[i] ^= other[i]
+	}
+	return u
+}
+
 // Must is a helper that wraps a call to a function returning (UUID, error)
 // and panics if the error is non-nil. It is intended for use in variable
 // initializations such as
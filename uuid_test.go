@@ -23,20 +23,49 @@ package uuid
 
 import (
 	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"strings"
 	"testing"
 	"time"
 )
 
 func TestUUID(t *testing.T) {
 	t.Run("IsNil", testUUIDIsNil)
+	t.Run("IsEmptyVersioned", testUUIDIsEmptyVersioned)
 	t.Run("Bytes", testUUIDBytes)
 	t.Run("String", testUUIDString)
+	t.Run("AppendHashLike", testUUIDAppendHashLike)
+	t.Run("AppendText", testUUIDAppendText)
 	t.Run("Version", testUUIDVersion)
 	t.Run("Variant", testUUIDVariant)
 	t.Run("SetVersion", testUUIDSetVersion)
 	t.Run("SetVariant", testUUIDSetVariant)
 	t.Run("Format", testUUIDFormat)
+	t.Run("FormatReference", testUUIDFormatReference)
+	t.Run("Compare", testUUIDCompare)
+	t.Run("CompareLE", testUUIDCompareLE)
+	t.Run("CommonPrefixBits", testCommonPrefixBits)
+	t.Run("EqualBytes", testUUIDEqualBytes)
+	t.Run("SortKeyPrefix", testUUIDSortKeyPrefix)
+	t.Run("EntropyBits", testUUIDEntropyBits)
+	t.Run("SecureBits", testUUIDSecureBits)
+	t.Run("WithRandomNode", testUUIDWithRandomNode)
+	t.Run("StripTimestamp", testUUIDStripTimestamp)
+	t.Run("Pretty", testUUIDPretty)
+	t.Run("ReversedBytes", testUUIDReversedBytes)
+	t.Run("CLiteral", testUUIDCLiteral)
+	t.Run("Pronounceable", testUUIDPronounceable)
+	t.Run("ColorSeed", testUUIDColorSeed)
+	t.Run("PayloadChecksum", testUUIDPayloadChecksum)
+	t.Run("BloomKeys", testUUIDBloomKeys)
+	t.Run("Bucket", testUUIDBucket)
+	t.Run("PathSegments", testUUIDPathSegments)
+	t.Run("XOR", testUUIDXOR)
 }
 
 func testUUIDIsNil(t *testing.T) {
@@ -48,6 +77,28 @@ func testUUIDIsNil(t *testing.T) {
 	}
 }
 
+func testUUIDIsEmptyVersioned(t *testing.T) {
+	tests := []struct {
+		s    string
+		want bool
+	}{
+		{s: "00000000-0000-4000-8000-000000000000", want: true},
+		{s: "00000000-0000-7000-0000-000000000000", want: true},
+		{s: "00000000-0000-1000-c000-000000000000", want: true},
+		{s: "00000000-0000-0000-0000-000000000000", want: false},
+		{s: "00000000-0000-0000-8000-000000000000", want: false},
+		{s: "00000000-0000-4000-8000-000000000001", want: false},
+		{s: "00000000-0000-4000-a000-000000000000", want: false},
+		{s: "6ba7b810-9dad-11d1-80b4-00c04fd430c8", want: false},
+	}
+	for _, tt := range tests {
+		u := Must(FromString(tt.s))
+		if got := u.IsEmptyVersioned(); got != tt.want {
+			t.Errorf("%v.IsEmptyVersioned() = %t, want %t", u, got, tt.want)
+		}
+	}
+}
+
 func testUUIDBytes(t *testing.T) {
 	got := codecTestUUID.Bytes()
 	want := codecTestData
@@ -64,6 +115,48 @@ func testUUIDString(t *testing.T) {
 	}
 }
 
+func testUUIDAppendHashLike(t *testing.T) {
+	want := "6ba7b8109dad11d180b400c04fd430c8"
+	if got := string(codecTestUUID.AppendHashLike(nil)); got != want {
+		t.Errorf("%v.AppendHashLike(nil) = %q, want %q", codecTestUUID, got, want)
+	}
+	if got := string(codecTestUUID.AppendHashLike([]byte("id="))); got != "id="+want {
+		t.Errorf("%v.AppendHashLike(%q) = %q, want %q", codecTestUUID, "id=", got, "id="+want)
+	}
+
+	for i := 0; i < 1000; i++ {
+		u := Must(NewV4())
+		s := string(u.AppendHashLike(nil))
+		got, err := FromString(s)
+		if err != nil {
+			t.Fatalf("FromString(%q): %v", s, err)
+		}
+		if got != u {
+			t.Fatalf("FromString(%v.AppendHashLike(nil)) = %v", u, got)
+		}
+	}
+
+	buf := make([]byte, 0, 32)
+	if n := testing.AllocsPerRun(100, func() { codecTestUUID.AppendHashLike(buf[:0]) }); n != 0 {
+		t.Errorf("AppendHashLike() with capacity allocated %v times, want 0", n)
+	}
+}
+
+func testUUIDAppendText(t *testing.T) {
+	want := codecTestUUID.String()
+	if got, err := codecTestUUID.AppendText(nil); err != nil || string(got) != want {
+		t.Errorf("%v.AppendText(nil) = %q, %v, want %q", codecTestUUID, got, err, want)
+	}
+	if got, _ := codecTestUUID.AppendText([]byte("id=")); string(got) != "id="+want {
+		t.Errorf("%v.AppendText(%q) = %q, want %q", codecTestUUID, "id=", got, "id="+want)
+	}
+
+	var buf [36]byte
+	if n := testing.AllocsPerRun(100, func() { codecTestUUID.AppendText(buf[:0]) }); n != 0 {
+		t.Errorf("AppendText() into a [36]byte allocated %v times, want 0", n)
+	}
+}
+
 func testUUIDVersion(t *testing.T) {
 	u := UUID{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x10, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}
 	if got, want := u.Version(), V1; got != want {
@@ -162,6 +255,575 @@ func testUUIDFormat(t *testing.T) {
 	}
 }
 
+func testUUIDCompare(t *testing.T) {
+	tests := []struct {
+		a, b UUID
+		want int
+	}{
+		{a: Nil, b: Nil, want: 0},
+		{a: codecTestUUID, b: codecTestUUID, want: 0},
+		{a: Nil, b: codecTestUUID, want: -1},
+		{a: codecTestUUID, b: Nil, want: 1},
+		{a: UUID{0x01}, b: UUID{15: 0xff}, want: 1},
+		{a: UUID{15: 0x01}, b: UUID{15: 0x02}, want: -1},
+	}
+	for _, tt := range tests {
+		if got := Compare(tt.a, tt.b); got != tt.want {
+			t.Errorf("Compare(%v, %v) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func testUUIDCompareLE(t *testing.T) {
+	tests := []struct {
+		a, b UUID
+		want int
+	}{
+		{a: Nil, b: Nil, want: 0},
+		{a: codecTestUUID, b: codecTestUUID, want: 0},
+		{a: UUID{0x01}, b: UUID{15: 0x01}, want: -1},
+		{a: UUID{15: 0x01}, b: UUID{0x01}, want: 1},
+		{a: UUID{0x02, 15: 0x01}, b: UUID{0x01, 15: 0x01}, want: 1},
+	}
+	for _, tt := range tests {
+		if got := CompareLE(tt.a, tt.b); got != tt.want {
+			t.Errorf("CompareLE(%v, %v) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+
+	// GUIDs sorted as little-endian 128-bit integers.
+	sorted := []UUID{
+		Must(FromString("ffffffff-ffff-ffff-ffff-ffffffffff00")),
+		Must(FromString("00000000-0000-0000-0000-0000000000ff")),
+		Must(FromString("01000000-0000-0000-0000-0000000000ff")),
+		Must(FromString("00000000-0000-0000-0000-0000000001ff")),
+		Must(FromString("00000000-0000-0000-0000-ffffffffffff")),
+	}
+	for i := 1; i < len(sorted); i++ {
+		if CompareLE(sorted[i-1], sorted[i]) >= 0 {
+			t.Errorf("CompareLE(%v, %v) >= 0, want < 0", sorted[i-1], sorted[i])
+		}
+	}
+}
+
+func testCommonPrefixBits(t *testing.T) {
+	tests := []struct {
+		a, b UUID
+		want int
+	}{
+		{a: codecTestUUID, b: codecTestUUID, want: 128},
+		{a: Nil, b: Nil, want: 128},
+		{a: Nil, b: Max, want: 0},
+		{a: UUID{0x7f}, b: UUID{0x80}, want: 0},
+		{a: UUID{0x01}, b: UUID{0x02}, want: 6},
+		{a: Nil, b: UUID{15: 0x01}, want: 127},
+		{a: codecTestUUID, b: NamespaceURL, want: 31}, // 6ba7b810 vs 6ba7b811
+	}
+	for _, tt := range tests {
+		if got := CommonPrefixBits(tt.a, tt.b); got != tt.want {
+			t.Errorf("CommonPrefixBits(%v, %v) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+		if got := CommonPrefixBits(tt.b, tt.a); got != tt.want {
+			t.Errorf("CommonPrefixBits(%v, %v) = %d, want %d", tt.b, tt.a, got, tt.want)
+		}
+	}
+
+	for bit := 0; bit < 128; bit++ {
+		var u UUID
+		u[bit/8] = 0x80 >> uint(bit%8)
+		if got := CommonPrefixBits(Nil, u); got != bit {
+			t.Errorf("CommonPrefixBits(%v, %v) = %d, want %d", Nil, u, got, bit)
+		}
+	}
+}
+
+func testUUIDEqualBytes(t *testing.T) {
+	tests := []struct {
+		b    []byte
+		want bool
+	}{
+		{b: codecTestData, want: true},
+		{b: Nil.Bytes(), want: false},
+		{b: codecTestData[:15], want: false},
+		{b: append(append([]byte(nil), codecTestData...), 0), want: false},
+		{b: nil, want: false},
+	}
+	for _, tt := range tests {
+		if got := codecTestUUID.EqualBytes(tt.b); got != tt.want {
+			t.Errorf("%v.EqualBytes(%x) = %t, want %t", codecTestUUID, tt.b, got, tt.want)
+		}
+	}
+	if !Nil.EqualBytes(make([]byte, Size)) {
+		t.Errorf("%v.EqualBytes(zeros) = false, want true", Nil)
+	}
+	if Nil.EqualBytes(nil) {
+		t.Errorf("%v.EqualBytes(nil) = true, want false", Nil)
+	}
+}
+
+func testUUIDSortKeyPrefix(t *testing.T) {
+	clamped := []struct {
+		n, want int
+	}{
+		{n: -1, want: 1},
+		{n: 0, want: 1},
+		{n: 1, want: 1},
+		{n: 8, want: 8},
+		{n: 16, want: 16},
+		{n: 17, want: 16},
+	}
+	for _, tt := range clamped {
+		got := codecTestUUID.SortKeyPrefix(tt.n)
+		if !bytes.Equal(got, codecTestData[:tt.want]) {
+			t.Errorf("%v.SortKeyPrefix(%d) = %x, want %x", codecTestUUID, tt.n, got, codecTestData[:tt.want])
+		}
+	}
+
+	v4 := make([]UUID, 50)
+	for i := range v4 {
+		v4[i] = Must(NewV4())
+	}
+	for _, a := range v4 {
+		for _, b := range v4 {
+			for n := 1; n <= Size; n++ {
+				if Compare(a, b) < 0 && bytes.Compare(a.SortKeyPrefix(n), b.SortKeyPrefix(n)) > 0 {
+					t.Fatalf("SortKeyPrefix(%d) orders %v after %v", n, a, b)
+				}
+			}
+		}
+	}
+
+	// V7 UUIDs from distinct milliseconds are strictly ordered by their
+	// 8 byte prefix.
+	now := time.Date(2021, 11, 26, 12, 34, 56, 0, time.UTC)
+	g := NewGen()
+	g.epochFunc = func() time.Time { return now }
+	var prev UUID
+	for i := 0; i < 50; i++ {
+		now = now.Add(time.Millisecond)
+		u := Must(g.NewV7(MillisecondPrecision))
+		if i > 0 {
+			if Compare(prev, u) >= 0 || bytes.Compare(prev.SortKeyPrefix(8), u.SortKeyPrefix(8)) >= 0 {
+				t.Fatalf("SortKeyPrefix(8) does not order %v before %v", prev, u)
+			}
+		}
+		prev = u
+	}
+}
+
+func testUUIDSecureBits(t *testing.T) {
+	g := NewGen()
+	g.RandomNodePreferred = true
+
+	tests := []struct {
+		u    UUID
+		want int
+	}{
+		{u: Must(NewV4()), want: 122},
+		{u: Must(NewV7(MillisecondPrecision)), want: 40},
+		{u: Must(NewV7(NanosecondPrecision)), want: 40},
+		{u: Must(NewV6()), want: 48},
+		{u: codecTestUUID, want: 0}, // V1 with a hardware address
+		{u: Must(g.NewV1()), want: 47},
+		{u: NewV3(NamespaceDNS, "www.example.com"), want: 0},
+		{u: NewV5(NamespaceDNS, "www.example.com"), want: 0},
+		{u: NewV8Name(NamespaceDNS, []byte("www.example.com"), sha256.New), want: 0},
+		{u: Nil, want: 0},
+		{u: Max, want: 0},
+	}
+	for _, tt := range tests {
+		if got := tt.u.SecureBits(); got != tt.want {
+			t.Errorf("V%d %v.SecureBits() = %d, want %d", tt.u.Version(), tt.u, got, tt.want)
+		}
+	}
+}
+
+func testUUIDEntropyBits(t *testing.T) {
+	stuck := func(n int) bool {
+		return n < EntropyBitsStuckLow || n > EntropyBitsStuckHigh
+	}
+
+	tests := []struct {
+		u     UUID
+		want  int
+		stuck bool
+	}{
+		{u: Nil, want: 0, stuck: true},
+		{u: Max, want: 122, stuck: true},
+		{u: codecTestUUID, want: 50, stuck: false},
+	}
+	for _, tt := range tests {
+		got := tt.u.EntropyBits()
+		if got != tt.want {
+			t.Errorf("%v.EntropyBits() = %d, want %d", tt.u, got, tt.want)
+		}
+		if stuck(got) != tt.stuck {
+			t.Errorf("%v.EntropyBits() = %d, stuck = %t, want %t", tt.u, got, stuck(got), tt.stuck)
+		}
+	}
+
+	for i := 0; i < 1000; i++ {
+		u := Must(NewV4())
+		if n := u.EntropyBits(); stuck(n) {
+			t.Fatalf("%v.EntropyBits() = %d, looks stuck", u, n)
+		}
+	}
+}
+
+func testUUIDWithRandomNode(t *testing.T) {
+	hwaddr := []byte{0x00, 0x11, 0x22, 0x33, 0x44, 0x55}
+	g := NewGenWithHWAF(func() (net.HardwareAddr, error) {
+		return hwaddr, nil
+	})
+
+	for _, u := range []UUID{Must(g.NewV1()), Must(g.NewV6())} {
+		got, err := u.WithRandomNode()
+		if err != nil {
+			t.Fatalf("%v.WithRandomNode(): %v", u, err)
+		}
+		if bytes.Equal(got[10:], u[10:]) {
+			t.Errorf("%v.WithRandomNode() = %v, node did not change", u, got)
+		}
+		if got[10]&0x01 == 0 {
+			t.Errorf("%v.WithRandomNode() = %v, multicast bit not set", u, got)
+		}
+		if !bytes.Equal(got[:10], u[:10]) {
+			t.Errorf("%v.WithRandomNode() = %v, timestamp or clock sequence changed", u, got)
+		}
+		want, _ := u.Time()
+		if ts, err := got.Time(); err != nil || !ts.Equal(want) {
+			t.Errorf("%v.WithRandomNode().Time() = %v, %v, want %v", u, ts, err, want)
+		}
+	}
+
+	for _, u := range []UUID{Must(NewV4()), Must(NewV7(MillisecondPrecision)), Nil} {
+		if got, err := u.WithRandomNode(); err == nil {
+			t.Errorf("%v.WithRandomNode() = %v, want error", u, got)
+		}
+	}
+}
+
+func testUUIDStripTimestamp(t *testing.T) {
+	tests := []struct {
+		u    UUID
+		want time.Time
+	}{
+		{u: Must(NewV1()), want: gregorianEpoch},
+		{u: Must(NewV6()), want: gregorianEpoch},
+		{u: Must(NewV7(MillisecondPrecision)), want: time.Unix(0, 0)},
+	}
+	for _, tt := range tests {
+		got, err := tt.u.StripTimestamp()
+		if err != nil {
+			t.Fatalf("%v.StripTimestamp(): %v", tt.u, err)
+		}
+		if got.Version() != tt.u.Version() || got.Variant() != VariantRFC4122 {
+			t.Errorf("%v.StripTimestamp() = %v, has version %d and variant %d", tt.u, got, got.Version(), got.Variant())
+		}
+		if ts, err := got.Time(); err != nil || !ts.Equal(tt.want) {
+			t.Errorf("%v.StripTimestamp().Time() = %v, %v, want %v", tt.u, ts, err, tt.want)
+		}
+		if !bytes.Equal(got[8:], tt.u[8:]) {
+			t.Errorf("%v.StripTimestamp() = %v, bytes 8-15 changed", tt.u, got)
+		}
+	}
+
+	for _, u := range []UUID{Must(NewV4()), NewV5(NamespaceDNS, "www.example.com"), Nil} {
+		if got, err := u.StripTimestamp(); err == nil {
+			t.Errorf("%v.StripTimestamp() = %v, want error", u, got)
+		}
+	}
+}
+
+func testUUIDPretty(t *testing.T) {
+	want := "uuid:    6ba7b810-9dad-11d1-80b4-00c04fd430c8\n" +
+		"version: 1\n" +
+		"variant: RFC4122\n" +
+		"time:    1998-02-04T22:13:53.1511824Z\n" +
+		"clock:   180\n" +
+		"node:    00:c0:4f:d4:30:c8\n" +
+		"bytes:   6b a7 b8 10 9d ad 11 d1 80 b4 00 c0 4f d4 30 c8\n"
+	if got := codecTestUUID.Pretty(); got != want {
+		t.Errorf("%v.Pretty() = %q, want %q", codecTestUUID, got, want)
+	}
+
+	v4 := Must(FromString("919108f7-52d1-4320-9bac-f847db4148a8"))
+	got := v4.Pretty()
+	for _, line := range []string{"uuid:    919108f7-52d1-4320-9bac-f847db4148a8\n", "version: 4\n", "variant: RFC4122\n", "bytes:   91 91 08 f7"} {
+		if !strings.Contains(got, line) {
+			t.Errorf("%v.Pretty() = %q, missing %q", v4, got, line)
+		}
+	}
+	for _, field := range []string{"time:", "clock:", "node:"} {
+		if strings.Contains(got, field) {
+			t.Errorf("%v.Pretty() = %q, should not contain %q", v4, got, field)
+		}
+	}
+
+	if got := Max.Pretty(); !strings.Contains(got, "variant: Future\n") {
+		t.Errorf("%v.Pretty() = %q, missing variant", Max, got)
+	}
+}
+
+func testUUIDPronounceable(t *testing.T) {
+	alias := codecTestUUID.Pronounceable()
+	if got := codecTestUUID.Pronounceable(); got != alias {
+		t.Errorf("%v.Pronounceable() = %q and %q across calls", codecTestUUID, alias, got)
+	}
+	if len(alias) != 14 || alias[4] != '-' || alias[9] != '-' {
+		t.Errorf("%v.Pronounceable() = %q, want the form cvcv-cvcv-cvcv", codecTestUUID, alias)
+	}
+
+	const n = 10000
+	seen := make(map[string]bool, n)
+	dups := 0
+	for i := 0; i < n; i++ {
+		a := Must(NewV4()).Pronounceable()
+		if seen[a] {
+			dups++
+		}
+		seen[a] = true
+	}
+	// with 36 bits, about 0.7 collisions are expected in 10000 aliases
+	if dups > 5 {
+		t.Errorf("Pronounceable() returned %d duplicate aliases for %d UUIDs", dups, n)
+	}
+}
+
+func testUUIDCLiteral(t *testing.T) {
+	tests := []struct {
+		u    UUID
+		want string
+	}{
+		{u: codecTestUUID, want: "{0x6b, 0xa7, 0xb8, 0x10, 0x9d, 0xad, 0x11, 0xd1, 0x80, 0xb4, 0x00, 0xc0, 0x4f, 0xd4, 0x30, 0xc8}"},
+		{u: Nil, want: "{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}"},
+	}
+	for _, tt := range tests {
+		if got := tt.u.CLiteral(); got != tt.want {
+			t.Errorf("%v.CLiteral() = %q, want %q", tt.u, got, tt.want)
+		}
+	}
+}
+
+func testUUIDReversedBytes(t *testing.T) {
+	want := Must(FromString("c830d44f-c000-b480-d111-ad9d10b8a76b"))
+	if got := codecTestUUID.ReversedBytes(); got != want {
+		t.Errorf("%v.ReversedBytes() = %v, want %v", codecTestUUID, got, want)
+	}
+	for _, u := range []UUID{Nil, Max, codecTestUUID, Must(NewV4())} {
+		if got := u.ReversedBytes().ReversedBytes(); got != u {
+			t.Errorf("%v.ReversedBytes().ReversedBytes() = %v", u, got)
+		}
+	}
+}
+
+func testUUIDColorSeed(t *testing.T) {
+	const want = 0x6aa2889c
+	if got := codecTestUUID.ColorSeed(); got != want {
+		t.Errorf("%v.ColorSeed() = %#x, want %#x", codecTestUUID, got, want)
+	}
+
+	const n = 1000
+	g := NewGen()
+	seeds := make(map[uint32]bool, n)
+	for i := 0; i < n; i++ {
+		u := Must(g.NewV1())
+		if u.ColorSeed() != u.ColorSeed() {
+			t.Fatalf("%v.ColorSeed() is not deterministic", u)
+		}
+		seeds[u.ColorSeed()] = true
+	}
+	// Collisions are possible, but should be very rare.
+	if len(seeds) < n-5 {
+		t.Errorf("got %d distinct seeds for %d UUIDs", len(seeds), n)
+	}
+}
+
+func testUUIDPayloadChecksum(t *testing.T) {
+	const want = 0x94
+	if got := codecTestUUID.PayloadChecksum(); got != want {
+		t.Errorf("%v.PayloadChecksum() = %#x, want %#x", codecTestUUID, got, want)
+	}
+
+	u := codecTestUUID
+	u[15] ^= 0xff
+	if got := u.PayloadChecksum(); got != want {
+		t.Errorf("PayloadChecksum() changed to %#x after corrupting byte 15", got)
+	}
+	for i := 0; i < Size-1; i++ {
+		for bit := uint(0); bit < 8; bit++ {
+			u := codecTestUUID
+			u[i] ^= 1 << bit
+			if got := u.PayloadChecksum(); got == want {
+				t.Errorf("PayloadChecksum() unchanged after flipping bit %d of byte %d", bit, i)
+			}
+		}
+	}
+}
+
+func testUUIDBloomKeys(t *testing.T) {
+	keys := codecTestUUID.BloomKeys(8)
+	if len(keys) != 8 {
+		t.Fatalf("%v.BloomKeys(8) returned %d keys, want 8", codecTestUUID, len(keys))
+	}
+	again := codecTestUUID.BloomKeys(8)
+	seen := make(map[uint64]bool)
+	for i, k := range keys {
+		if again[i] != k {
+			t.Errorf("%v.BloomKeys(8)[%d] = %#x and %#x across two calls", codecTestUUID, i, k, again[i])
+		}
+		if seen[k] {
+			t.Errorf("%v.BloomKeys(8) = %#x, contains duplicate key %#x", codecTestUUID, keys, k)
+		}
+		seen[k] = true
+	}
+
+	other := NamespaceURL.BloomKeys(8)
+	for i := range keys {
+		if other[i] == keys[i] {
+			t.Errorf("BloomKeys(8)[%d] = %#x for both %v and %v", i, keys[i], codecTestUUID, NamespaceURL)
+		}
+	}
+
+	for _, k := range []int{0, -1} {
+		if got := codecTestUUID.BloomKeys(k); got != nil {
+			t.Errorf("%v.BloomKeys(%d) = %#x, want nil", codecTestUUID, k, got)
+		}
+	}
+}
+
+func testUUIDBucket(t *testing.T) {
+	weights := []int{10, 0, 30, 60}
+
+	b := codecTestUUID.Bucket(weights)
+	for i := 0; i < 10; i++ {
+		if got := codecTestUUID.Bucket(weights); got != b {
+			t.Fatalf("%v.Bucket(%v) = %d and %d across calls", codecTestUUID, weights, b, got)
+		}
+	}
+
+	const n = 100000
+	counts := make([]int, len(weights))
+	for i := 0; i < n; i++ {
+		counts[Must(NewV4()).Bucket(weights)]++
+	}
+	for i, w := range weights {
+		want := n * w / 100
+		if diff := counts[i] - want; diff < -n/100 || diff > n/100 {
+			t.Errorf("Bucket(%v) selected index %d %d times out of %d, want about %d", weights, i, counts[i], n, want)
+		}
+	}
+
+	for _, w := range [][]int{nil, {}, {0, 0}, {-1, 0}} {
+		if got := codecTestUUID.Bucket(w); got != -1 {
+			t.Errorf("%v.Bucket(%v) = %d, want -1", codecTestUUID, w, got)
+		}
+	}
+	if got := codecTestUUID.Bucket([]int{0, -5, 1}); got != 2 {
+		t.Errorf("%v.Bucket(%v) = %d, want 2", codecTestUUID, []int{0, -5, 1}, got)
+	}
+}
+
+func testUUIDPathSegments(t *testing.T) {
+	tests := []struct {
+		levels, width int
+		want          []string
+	}{
+		{levels: 2, width: 2, want: []string{"6b", "a7", "b8109dad11d180b400c04fd430c8"}},
+		{levels: 1, width: 3, want: []string{"6ba", "7b8109dad11d180b400c04fd430c8"}},
+		{levels: 3, width: 1, want: []string{"6", "b", "a", "7b8109dad11d180b400c04fd430c8"}},
+		{levels: 0, width: 2, want: []string{"6ba7b8109dad11d180b400c04fd430c8"}},
+		{levels: -1, width: 2, want: []string{"6ba7b8109dad11d180b400c04fd430c8"}},
+		{levels: 2, width: 0, want: []string{"6", "b", "a7b8109dad11d180b400c04fd430c8"}},
+		{levels: 2, width: 16, want: []string{"6ba7b8109dad11d1", "80b400c04fd430c8"}},
+		{levels: 3, width: 16, want: []string{"6ba7b8109dad11d1", "80b400c04fd430c8"}},
+		{levels: 1, width: 100, want: []string{"6ba7b8109dad11d180b400c04fd430c8"}},
+	}
+	for _, tt := range tests {
+		got := codecTestUUID.PathSegments(tt.levels, tt.width)
+		if strings.Join(got, "/") != strings.Join(tt.want, "/") || len(got) != len(tt.want) {
+			t.Errorf("%v.PathSegments(%d, %d) = %q, want %q", codecTestUUID, tt.levels, tt.width, got, tt.want)
+		}
+	}
+}
+
+// referenceFormat is the original, allocating, implementation of Format used
+// to verify the output of the current implementation.
+type referenceFormat UUID
+
+func (r referenceFormat) Format(f fmt.State, c rune) {
+	u := UUID(r)
+	upper := func(s string) string {
+		return strings.Map(func(ch rune) rune {
+			if 'a' <= ch && ch <= 'f' {
+				return ch - ('a' - 'A')
+			}
+			return ch
+		}, s)
+	}
+	switch c {
+	case 'x', 'X':
+		s := hex.EncodeToString(u.Bytes())
+		if c == 'X' {
+			s = upper(s)
+		}
+		_, _ = io.WriteString(f, s)
+	case 'v':
+		var s string
+		if f.Flag('#') {
+			s = fmt.Sprintf("%#v", [Size]byte(u))
+		} else {
+			s = u.String()
+		}
+		_, _ = io.WriteString(f, s)
+	case 's', 'S':
+		s := u.String()
+		if c == 'S' {
+			s = upper(s)
+		}
+		_, _ = io.WriteString(f, s)
+	case 'q':
+		_, _ = io.WriteString(f, `"`+u.String()+`"`)
+	default:
+		fmt.Fprintf(f, "%%!%c(uuid.UUID=%s)", c, u.String())
+	}
+}
+
+func testUUIDFormatReference(t *testing.T) {
+	verbs := []string{"%s", "%S", "%q", "%x", "%X", "%v", "%+v", "%#v", "%d", "%10s", "%-40x"}
+	uuids := []UUID{Nil, codecTestUUID}
+	for i := 0; i < 100; i++ {
+		uuids = append(uuids, Must(NewV4()))
+	}
+	for _, u := range uuids {
+		for _, verb := range verbs {
+			got := fmt.Sprintf(verb, u)
+			want := fmt.Sprintf(verb, referenceFormat(u))
+			if got != want {
+				t.Errorf("Sprintf(%q, %v) = %q, want %q", verb, u, got, want)
+			}
+		}
+	}
+}
+
+func testUUIDXOR(t *testing.T) {
+	a := Must(FromString("12345678-90ab-cdef-1234-567890abcdef"))
+	b := codecTestUUID
+	want := Must(FromString("7993ee68-0d06-dc3e-9280-56b8df7ffd27"))
+	if got := a.XOR(b); got != want {
+		t.Errorf("%v.XOR(%v) = %v, want %v", a, b, got, want)
+	}
+	if a.XOR(b) != b.XOR(a) {
+		t.Errorf("%v.XOR(%v) != %v.XOR(%v)", a, b, b, a)
+	}
+	if got := a.XOR(a); got != Nil {
+		t.Errorf("%v.XOR(%v) = %v, want Nil", a, a, got)
+	}
+	if got := a.XOR(Nil); got != a {
+		t.Errorf("%v.XOR(Nil) = %v, want %v", a, got, a)
+	}
+}
+
 func TestMust(t *testing.T) {
 	sentinel := fmt.Errorf("uuid: sentinel error")
 	defer func() {
@@ -183,6 +845,45 @@ func TestMust(t *testing.T) {
 	Must(fn())
 }
 
+func TestTestVectors(t *testing.T) {
+	vectors := TestVectors()
+	if len(vectors) != 9 || vectors[0] != Nil || vectors[len(vectors)-1] != Max {
+		t.Fatalf("TestVectors() = %v, want Nil, V1, V3-V8, and Max", vectors)
+	}
+
+	versions := make(map[byte]int)
+	for _, u := range vectors[1 : len(vectors)-1] {
+		if u.Variant() != VariantRFC4122 {
+			t.Errorf("%v.Variant() = %d, want %d", u, u.Variant(), VariantRFC4122)
+		}
+		versions[u.Version()]++
+	}
+	for _, v := range []byte{V1, V3, V4, V5, V6, V7, V8} {
+		if versions[v] != 1 {
+			t.Errorf("TestVectors() has %d UUIDs of version %d, want 1", versions[v], v)
+		}
+	}
+
+	for _, u := range vectors {
+		got, err := FromString(u.String())
+		if err != nil || got != u {
+			t.Errorf("FromString(%q) = %v, %v, want %v", u.String(), got, err, u)
+		}
+	}
+
+	if u := NewV3(NamespaceDNS, "www.example.com"); vectors[2] != u {
+		t.Errorf("TestVectors() V3 = %v, want %v", vectors[2], u)
+	}
+	if u := NewV5(NamespaceDNS, "www.example.com"); vectors[4] != u {
+		t.Errorf("TestVectors() V5 = %v, want %v", vectors[4], u)
+	}
+
+	vectors[1] = Nil
+	if TestVectors()[1] == Nil {
+		t.Error("TestVectors() returned a shared slice")
+	}
+}
+
 func TestTimeFromTimestamp(t *testing.T) {
 	tests := []struct {
 		t    Timestamp
@@ -209,6 +910,19 @@ func TestTimeFromTimestamp(t *testing.T) {
 	}
 }
 
+func TestGregorianEpochOffset(t *testing.T) {
+	want := time.Date(1582, 10, 15, 0, 0, 0, 0, time.UTC)
+	if got := time.Unix(-GregorianEpochOffset/_100nsPerSecond, 0); !got.Equal(want) {
+		t.Errorf("time.Unix(-GregorianEpochOffset) = %v, want %v", got, want)
+	}
+	if got, _ := Timestamp(0).Time(); !got.Equal(want) {
+		t.Errorf("Timestamp(0).Time() = %v, want %v", got, want)
+	}
+	if got, _ := Timestamp(GregorianEpochOffset).Time(); !got.Equal(time.Unix(0, 0)) {
+		t.Errorf("Timestamp(GregorianEpochOffset).Time() = %v, want %v", got, time.Unix(0, 0))
+	}
+}
+
 func TestTimestampFromV1(t *testing.T) {
 	tests := []struct {
 		u       UUID
@@ -254,3 +968,419 @@ func TestTimestampFromV6(t *testing.T) {
 		}
 	}
 }
+
+func TestTimeFromV7(t *testing.T) {
+	now := time.Date(2021, 11, 26, 12, 34, 56, 123456789, time.UTC)
+	g := NewGen()
+	g.epochFunc = func() time.Time { return now }
+
+	tests := []struct {
+		p    Precision
+		want time.Time
+	}{
+		{p: NanosecondPrecision, want: now},
+		{p: MicrosecondPrecision, want: now.Truncate(time.Microsecond)},
+		{p: MillisecondPrecision, want: now.Truncate(time.Millisecond)},
+	}
+	for _, tt := range tests {
+		u := Must(g.NewV7(tt.p))
+		got, err := TimeFromV7(u, tt.p)
+		if err != nil {
+			t.Fatalf("TimeFromV7(%v, %v): %v", u, tt.p, err)
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("TimeFromV7(%v, %v) = %v, want %v", u, tt.p, got, tt.want)
+		}
+	}
+
+	if got, err := TimeFromV7(Must(NewV4()), MillisecondPrecision); err == nil {
+		t.Errorf("TimeFromV7(V4) = %v, want error", got)
+	}
+	if got, err := TimeFromV7(Must(g.NewV7(MillisecondPrecision)), 0xff); err == nil {
+		t.Errorf("TimeFromV7(V7, 0xff) = %v, want error", got)
+	}
+}
+
+func TestTimeFromV7Fraction(t *testing.T) {
+	now := time.Date(2021, 11, 26, 12, 34, 56, 123456789, time.UTC)
+	g := NewGenWithOptions(WithEpochFunc(func() time.Time { return now }), WithSubMillisecondFraction())
+
+	// the fraction has a resolution of 1ms / 4096
+	u := Must(g.NewV7(MillisecondPrecision))
+	got, err := TimeFromV7Fraction(u)
+	if err != nil {
+		t.Fatalf("TimeFromV7Fraction(%v): %v", u, err)
+	}
+	if want := time.Date(2021, 11, 26, 12, 34, 56, 123456787, time.UTC); !got.Equal(want) {
+		t.Errorf("TimeFromV7Fraction(%v) = %v, want %v", u, got, want)
+	}
+
+	if got, err := TimeFromV7Fraction(Must(NewV4())); err == nil {
+		t.Errorf("TimeFromV7Fraction(V4) = %v, want error", got)
+	}
+}
+
+func TestUUIDTime(t *testing.T) {
+	now := time.Date(2021, 11, 26, 12, 34, 56, 123456789, time.UTC)
+	g := NewGen()
+	g.epochFunc = func() time.Time { return now }
+
+	tests := []struct {
+		u    UUID
+		want string
+	}{
+		{u: Must(g.NewV1()), want: "2021-11-26T12:34:56.1234567Z"},
+		{u: Must(g.NewV6()), want: "2021-11-26T12:34:56.1234567Z"},
+		{u: Must(g.NewV7(MillisecondPrecision)), want: "2021-11-26T12:34:56.123Z"},
+	}
+	for _, tt := range tests {
+		got, err := tt.u.CreatedAt()
+		if err != nil {
+			t.Fatalf("%v.CreatedAt(): %v", tt.u, err)
+		}
+		if got != tt.want {
+			t.Errorf("%v.CreatedAt() = %q, want %q", tt.u, got, tt.want)
+		}
+	}
+
+	u := Must(NewV4())
+	if got, err := u.Time(); err == nil {
+		t.Errorf("%v.Time() = %v, want error", u, got)
+	}
+	if got, err := u.CreatedAt(); err == nil {
+		t.Errorf("%v.CreatedAt() = %q, want error", u, got)
+	}
+}
+
+func TestUUIDTimeOrZero(t *testing.T) {
+	now := time.Date(2021, 11, 26, 12, 34, 56, 123456789, time.UTC)
+	g := NewGen()
+	g.epochFunc = func() time.Time { return now }
+
+	tests := []struct {
+		u    UUID
+		want time.Time
+	}{
+		{u: Must(g.NewV1()), want: now.Truncate(100 * time.Nanosecond)},
+		{u: Must(g.NewV6()), want: now.Truncate(100 * time.Nanosecond)},
+		{u: Must(g.NewV7(MillisecondPrecision)), want: now.Truncate(time.Millisecond)},
+		{u: Must(g.NewV4()), want: time.Time{}},
+		{u: Nil, want: time.Time{}},
+	}
+	for _, tt := range tests {
+		if got := tt.u.TimeOrZero(); !got.Equal(tt.want) {
+			t.Errorf("%v.TimeOrZero() = %v, want %v", tt.u, got, tt.want)
+		}
+	}
+}
+
+func TestUUIDTimeBucketPow2(t *testing.T) {
+	base := time.UnixMilli(1637930096000 &^ 1023) // aligned to 1024ms
+	g := NewGen()
+	newV7 := func(t time.Time) UUID {
+		g.epochFunc = func() time.Time { return t }
+		return Must(g.NewV7(MillisecondPrecision))
+	}
+
+	a := newV7(base.Add(3 * time.Millisecond))
+	b := newV7(base.Add(1020 * time.Millisecond))
+	c := newV7(base.Add(1024 * time.Millisecond))
+
+	ba, err := a.TimeBucketPow2(10)
+	if err != nil {
+		t.Fatalf("%v.TimeBucketPow2(10): %v", a, err)
+	}
+	if want := uint64(base.UnixMilli()); ba != want {
+		t.Errorf("%v.TimeBucketPow2(10) = %d, want %d", a, ba, want)
+	}
+	if bb, _ := b.TimeBucketPow2(10); bb != ba {
+		t.Errorf("%v.TimeBucketPow2(10) = %d, want %d", b, bb, ba)
+	}
+	if bc, _ := c.TimeBucketPow2(10); bc != ba+1024 {
+		t.Errorf("%v.TimeBucketPow2(10) = %d, want %d", c, bc, ba+1024)
+	}
+	if got, _ := a.TimeBucketPow2(0); got != uint64(base.UnixMilli())+3 {
+		t.Errorf("%v.TimeBucketPow2(0) = %d, want %d", a, got, base.UnixMilli()+3)
+	}
+
+	bad := a
+	bad[4], bad[5] = bad[4]|0x0f, 0xff // msec of 4095
+	for _, u := range []UUID{Must(NewV4()), bad} {
+		if got, err := u.TimeBucketPow2(10); err == nil {
+			t.Errorf("%v.TimeBucketPow2(10) = %d, want error", u, got)
+		}
+	}
+}
+
+func TestUUIDAge(t *testing.T) {
+	created := time.Date(2021, 11, 26, 12, 34, 56, 0, time.UTC)
+	now := created.Add(90 * time.Minute)
+	g := NewGen()
+	g.epochFunc = func() time.Time { return created }
+
+	for _, u := range []UUID{Must(g.NewV1()), Must(g.NewV6()), Must(g.NewV7(MillisecondPrecision))} {
+		got, err := u.Age(now)
+		if err != nil {
+			t.Fatalf("%v.Age(): %v", u, err)
+		}
+		if got != 90*time.Minute {
+			t.Errorf("V%d %v.Age(%v) = %v, want %v", u.Version(), u, now, got, 90*time.Minute)
+		}
+	}
+
+	u := Must(NewV4())
+	if got, err := u.Age(now); err == nil {
+		t.Errorf("%v.Age() = %v, want error", u, got)
+	}
+}
+
+func TestPrecedesInTime(t *testing.T) {
+	newGen := func(tn time.Time) *Gen {
+		g := NewGen()
+		g.epochFunc = func() time.Time { return tn }
+		return g
+	}
+	t1 := time.Date(2021, 11, 26, 12, 0, 0, 0, time.UTC)
+	t2 := t1.Add(time.Second)
+	g1, g2 := newGen(t1), newGen(t2)
+
+	tests := []struct {
+		a, b UUID
+		want bool
+	}{
+		{a: Must(g1.NewV1()), b: Must(g2.NewV6()), want: true},
+		{a: Must(g1.NewV6()), b: Must(g2.NewV7(MillisecondPrecision)), want: true},
+		{a: Must(g1.NewV7(MillisecondPrecision)), b: Must(g2.NewV1()), want: true},
+		{a: Must(g2.NewV1()), b: Must(g1.NewV7(MillisecondPrecision)), want: false},
+		{a: Must(g1.NewV1()), b: Must(g1.NewV7(MillisecondPrecision)), want: false},
+	}
+	for _, tt := range tests {
+		got, err := PrecedesInTime(tt.a, tt.b)
+		if err != nil {
+			t.Fatalf("PrecedesInTime(%v, %v): %v", tt.a, tt.b, err)
+		}
+		if got != tt.want {
+			t.Errorf("PrecedesInTime(%v, %v) = %t, want %t", tt.a, tt.b, got, tt.want)
+		}
+	}
+
+	v1, v4 := Must(g1.NewV1()), Must(NewV4())
+	if _, err := PrecedesInTime(v4, v1); err == nil {
+		t.Errorf("PrecedesInTime(%v, %v): want error", v4, v1)
+	}
+	if _, err := PrecedesInTime(v1, v4); err == nil {
+		t.Errorf("PrecedesInTime(%v, %v): want error", v1, v4)
+	}
+}
+
+func TestCompareTimeThenBytes(t *testing.T) {
+	t1 := time.Date(2021, 11, 26, 12, 0, 0, 0, time.UTC)
+	t2 := t1.Add(time.Millisecond)
+	newV7 := func(tn time.Time, tail byte) UUID {
+		u, _, err := TimeBounds(V7, tn)
+		if err != nil {
+			t.Fatal(err)
+		}
+		u[15] = tail
+		return u
+	}
+
+	a, b := newV7(t1, 0x01), newV7(t1, 0x02)
+	later := newV7(t2, 0x00)
+	v1 := Must(FromString("00000000-0000-1000-8000-000000000000")) // 1582
+
+	tests := []struct {
+		a, b UUID
+		want int
+	}{
+		{a: a, b: a, want: 0},
+		{a: a, b: b, want: -1},
+		{a: b, b: a, want: 1},
+		{a: b, b: later, want: -1},
+		{a: later, b: a, want: 1},
+		{a: later, b: v1, want: 1},
+	}
+	for _, tt := range tests {
+		got, err := CompareTimeThenBytes(tt.a, tt.b)
+		if err != nil {
+			t.Fatalf("CompareTimeThenBytes(%v, %v): %v", tt.a, tt.b, err)
+		}
+		if got != tt.want {
+			t.Errorf("CompareTimeThenBytes(%v, %v) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+
+	v4 := Must(NewV4())
+	if _, err := CompareTimeThenBytes(a, v4); err == nil {
+		t.Errorf("CompareTimeThenBytes(%v, %v): want error", a, v4)
+	}
+	if _, err := CompareTimeThenBytes(v4, a); err == nil {
+		t.Errorf("CompareTimeThenBytes(%v, %v): want error", v4, a)
+	}
+}
+
+func TestUUIDWithinWindow(t *testing.T) {
+	now := time.Date(2021, 11, 26, 12, 34, 56, 123000000, time.UTC)
+	newV7 := func(tn time.Time) UUID {
+		g := NewGen()
+		g.epochFunc = func() time.Time { return tn }
+		return Must(g.NewV7(MillisecondPrecision))
+	}
+
+	tests := []struct {
+		name string
+		u    UUID
+		want bool
+	}{
+		{name: "Now", u: newV7(now), want: true},
+		{name: "Fresh", u: newV7(now.Add(-30 * time.Second)), want: true},
+		{name: "Oldest", u: newV7(now.Add(-time.Minute)), want: true},
+		{name: "Expired", u: newV7(now.Add(-time.Minute - time.Millisecond)), want: false},
+		{name: "Future", u: newV7(now.Add(time.Millisecond)), want: false},
+	}
+	for _, tt := range tests {
+		got, err := tt.u.WithinWindow(now, time.Minute)
+		if err != nil {
+			t.Fatalf("%s: %v.WithinWindow(): %v", tt.name, tt.u, err)
+		}
+		if got != tt.want {
+			t.Errorf("%s: %v.WithinWindow() = %t, want %t", tt.name, tt.u, got, tt.want)
+		}
+	}
+
+	u := Must(NewV4())
+	if _, err := u.WithinWindow(now, time.Minute); err == nil {
+		t.Errorf("%v.WithinWindow(): want error", u)
+	}
+}
+
+func TestIsSuccessorV7(t *testing.T) {
+	now := time.Date(2021, 11, 26, 12, 34, 56, 0, time.UTC)
+	g := NewGen()
+	g.epochFunc = func() time.Time { return now }
+
+	a := Must(g.NewV7(MillisecondPrecision))
+	b := Must(g.NewV7(MillisecondPrecision)) // same millisecond, next seq
+	now = now.Add(time.Millisecond)
+	c := Must(g.NewV7(MillisecondPrecision))
+
+	tests := []struct {
+		prev, next UUID
+		want       bool
+	}{
+		{prev: a, next: b, want: true},
+		{prev: b, next: c, want: true},
+		{prev: a, next: c, want: true},
+		{prev: a, next: a, want: false},
+		{prev: b, next: a, want: false},
+		{prev: c, next: a, want: false},
+	}
+	for _, tt := range tests {
+		got, err := IsSuccessorV7(tt.prev, tt.next)
+		if err != nil {
+			t.Fatalf("IsSuccessorV7(%v, %v): %v", tt.prev, tt.next, err)
+		}
+		if got != tt.want {
+			t.Errorf("IsSuccessorV7(%v, %v) = %t, want %t", tt.prev, tt.next, got, tt.want)
+		}
+	}
+
+	v4 := Must(NewV4())
+	if _, err := IsSuccessorV7(a, v4); err == nil {
+		t.Errorf("IsSuccessorV7(%v, %v): want error", a, v4)
+	}
+	if _, err := IsSuccessorV7(v4, a); err == nil {
+		t.Errorf("IsSuccessorV7(%v, %v): want error", v4, a)
+	}
+}
+
+func TestTimeBounds(t *testing.T) {
+	now := time.Date(2021, 11, 26, 12, 34, 56, 123456789, time.UTC)
+	g := NewGen()
+	g.epochFunc = func() time.Time { return now }
+
+	tests := []struct {
+		version byte
+		gen     func() (UUID, error)
+		next    time.Duration
+	}{
+		{version: V1, gen: g.NewV1, next: 100 * time.Nanosecond},
+		{version: V6, gen: g.NewV6, next: 100 * time.Nanosecond},
+		{version: V7, gen: func() (UUID, error) { return g.NewV7(MillisecondPrecision) }, next: time.Millisecond},
+	}
+	for _, tt := range tests {
+		low, high, err := TimeBounds(tt.version, now)
+		if err != nil {
+			t.Fatalf("TimeBounds(%d, %v): %v", tt.version, now, err)
+		}
+		for _, u := range []UUID{low, high} {
+			if u.Version() != tt.version || u.Variant() != VariantRFC4122 {
+				t.Errorf("TimeBounds(%d, %v): %v has version %d and variant %d", tt.version, now, u, u.Version(), u.Variant())
+			}
+			if ts, err := u.Time(); err != nil || !ts.Equal(now.Truncate(tt.next)) {
+				t.Errorf("TimeBounds(%d, %v): %v.Time() = %v, %v", tt.version, now, u, ts, err)
+			}
+		}
+		for i := 0; i < 100; i++ {
+			u := Must(tt.gen())
+			if Compare(u, low) < 0 || Compare(u, high) > 0 {
+				t.Fatalf("TimeBounds(%d, %v) = %v, %v: does not contain %v", tt.version, now, low, high, u)
+			}
+		}
+
+		nextLow, _, err := TimeBounds(tt.version, now.Add(tt.next))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tt.version != V1 && Compare(nextLow, high) <= 0 {
+			t.Errorf("TimeBounds(%d, %v) high %v overlaps next low %v", tt.version, now, high, nextLow)
+		}
+	}
+
+	invalid := []struct {
+		version byte
+		t       time.Time
+	}{
+		{version: V4, t: now},
+		{version: V3, t: now},
+		{version: V1, t: time.Date(1500, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{version: V6, t: time.Date(5300, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{version: V7, t: time.Unix(-1, 0)},
+	}
+	for _, tt := range invalid {
+		if low, high, err := TimeBounds(tt.version, tt.t); err == nil {
+			t.Errorf("TimeBounds(%d, %v) = %v, %v, want error", tt.version, tt.t, low, high)
+		}
+	}
+}
+
+func TestTimeBoundsEarly(t *testing.T) {
+	// before the earliest time representable by time.Time.UnixNano
+	early := time.Date(1600, 1, 1, 0, 0, 0, 0, time.UTC)
+	low, _, err := TimeBounds(V1, early)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := low.Time(); !got.Equal(early) {
+		t.Errorf("TimeBounds(1, %v) low time = %v, want %v", early, got, early)
+	}
+}
+
+func BenchmarkAppendHashLike(b *testing.B) {
+	b.ReportAllocs()
+	buf := make([]byte, 0, 32)
+	for i := 0; i < b.N; i++ {
+		buf = codecTestUUID.AppendHashLike(buf[:0])
+	}
+}
+
+func BenchmarkFormat(b *testing.B) {
+	for _, verb := range []string{"%s", "%S", "%q", "%x", "%X", "%v", "%+v", "%#v"} {
+		b.Run(verb, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				fmt.Fprintf(ioutil.Discard, verb, codecTestUUID)
+			}
+		})
+	}
+}
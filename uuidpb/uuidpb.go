@@ -0,0 +1,58 @@
+// Copyright (C) 2013-2018 by Maxim Bublis <b@codemonkey.ru>
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the
+// "Software"), to deal in the Software without restriction, including
+// without limitation the rights to use, copy, modify, merge, publish,
+// distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to
+// the following conditions:
+//
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+// MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION
+// WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+// Package uuidpb converts UUIDs to and from the 16 byte protobuf bytes fields
+// and google.protobuf.BytesValue wrappers used to pass them across gRPC
+// boundaries. It is a separate package so that the protobuf dependency is
+// only pulled in by programs that need it.
+package uuidpb
+
+import (
+	"github.com/gofrs/uuid"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// ProtoBytes returns u as a 16 byte slice for use as a protobuf bytes field.
+func ProtoBytes(u uuid.UUID) []byte {
+	return u.Bytes()
+}
+
+// FromProtoBytes returns the UUID held by a protobuf bytes field, as produced
+// by ProtoBytes. An empty slice, which is how proto3 represents an unset bytes
+// field, is decoded as uuid.Nil. Any other length than 0 or 16 is an error.
+func FromProtoBytes(b []byte) (uuid.UUID, error) {
+	if len(b) == 0 {
+		return uuid.Nil, nil
+	}
+	return uuid.FromBytes(b)
+}
+
+// ToBytesValue returns u wrapped in a google.protobuf.BytesValue.
+func ToBytesValue(u uuid.UUID) *wrapperspb.BytesValue {
+	return wrapperspb.Bytes(ProtoBytes(u))
+}
+
+// FromBytesValue returns the UUID held by a google.protobuf.BytesValue, as
+// produced by ToBytesValue. A nil wrapper, which is how an unset field of the
+// wrapper type is represented, and an empty value are decoded as uuid.Nil.
+func FromBytesValue(v *wrapperspb.BytesValue) (uuid.UUID, error) {
+	return FromProtoBytes(v.GetValue())
+}
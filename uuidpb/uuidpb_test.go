@@ -0,0 +1,94 @@
+// Copyright (C) 2013-2018 by Maxim Bublis <b@codemonkey.ru>
+//
+// Permission is hereby granted, free of charge, to any person obtaining
+// a copy of this software and associated documentation files (the
+// "Software"), to deal in the Software without restriction, including
+// without limitation the rights to use, copy, modify, merge, publish,
+// distribute, sublicense, and/or sell copies of the Software, and to
+// permit persons to whom the Software is furnished to do so, subject to
+// the following conditions:
+//
+// The above copyright notice and this permission notice shall be
+// included in all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+// MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND
+// NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE
+// LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION
+// OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION
+// WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+
+package uuidpb
+
+import (
+	"testing"
+
+	"github.com/gofrs/uuid"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+var testUUIDs = []uuid.UUID{
+	uuid.Nil,
+	uuid.Max,
+	uuid.Must(uuid.FromString("6ba7b810-9dad-11d1-80b4-00c04fd430c8")),
+	uuid.Must(uuid.NewV4()),
+}
+
+func TestProtoBytes(t *testing.T) {
+	for _, u := range testUUIDs {
+		b := ProtoBytes(u)
+		if len(b) != uuid.Size {
+			t.Fatalf("ProtoBytes(%v) returned %d bytes, want %d", u, len(b), uuid.Size)
+		}
+		got, err := FromProtoBytes(b)
+		if err != nil {
+			t.Fatalf("FromProtoBytes(%x): %v", b, err)
+		}
+		if got != u {
+			t.Errorf("FromProtoBytes(%x) = %v, want %v", b, got, u)
+		}
+	}
+
+	for _, b := range [][]byte{nil, {}} {
+		if got, err := FromProtoBytes(b); err != nil || got != uuid.Nil {
+			t.Errorf("FromProtoBytes(%#v) = %v, %v, want Nil", b, got, err)
+		}
+	}
+	for _, b := range [][]byte{{0x01}, make([]byte, 15), make([]byte, 17)} {
+		if got, err := FromProtoBytes(b); err == nil {
+			t.Errorf("FromProtoBytes(%x) = %v, want error", b, got)
+		}
+	}
+}
+
+func TestBytesValue(t *testing.T) {
+	for _, u := range testUUIDs {
+		// round trip through the wire format
+		data, err := proto.Marshal(ToBytesValue(u))
+		if err != nil {
+			t.Fatalf("proto.Marshal(ToBytesValue(%v)): %v", u, err)
+		}
+		v := new(wrapperspb.BytesValue)
+		if err := proto.Unmarshal(data, v); err != nil {
+			t.Fatalf("proto.Unmarshal(%x): %v", data, err)
+		}
+		got, err := FromBytesValue(v)
+		if err != nil {
+			t.Fatalf("FromBytesValue(%v): %v", v, err)
+		}
+		if got != u {
+			t.Errorf("FromBytesValue(%v) = %v, want %v", v, got, u)
+		}
+	}
+
+	for _, v := range []*wrapperspb.BytesValue{nil, wrapperspb.Bytes(nil)} {
+		if got, err := FromBytesValue(v); err != nil || got != uuid.Nil {
+			t.Errorf("FromBytesValue(%v) = %v, %v, want Nil", v, got, err)
+		}
+	}
+	if got, err := FromBytesValue(wrapperspb.Bytes(make([]byte, 15))); err == nil {
+		t.Errorf("FromBytesValue() of 15 bytes = %v, want error", got)
+	}
+}